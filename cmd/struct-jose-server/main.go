@@ -0,0 +1,24 @@
+// Command struct-jose-server hosts the voxgigstruct Struct gRPC service,
+// wrapping a default SDK instance so non-Go clients can drive validation,
+// injection, and path lookups.
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/voxgig/struct/service"
+	runner "github.com/voxgig/struct/testutil"
+)
+
+func main() {
+	addr := flag.String("addr", ":50051", "address to listen on")
+	flag.Parse()
+
+	sdk := runner.NewSDK(nil)
+
+	log.Printf("struct-jose-server listening on %s", *addr)
+	if err := service.Serve(*addr, sdk.Utility()); err != nil {
+		log.Fatalf("struct-jose-server: %v", err)
+	}
+}
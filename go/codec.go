@@ -0,0 +1,144 @@
+package voxgigstruct
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Marshal encodes v as JSON, YAML, or TOML, selected by format
+// ("json"/"yaml"/"yml"/"toml", case-insensitive).
+func Marshal(v any, format string) ([]byte, error) {
+	switch strings.ToLower(format) {
+	case "", "json":
+		return json.Marshal(v)
+
+	case "yaml", "yml":
+		return yaml.Marshal(v)
+
+	case "toml":
+		var buf bytes.Buffer
+		if err := toml.NewEncoder(&buf).Encode(v); err != nil {
+			return nil, fmt.Errorf("voxgigstruct.Marshal: toml: %w", err)
+		}
+		return buf.Bytes(), nil
+
+	default:
+		return nil, fmt.Errorf("voxgigstruct.Marshal: unknown format %q", format)
+	}
+}
+
+// Unmarshal decodes JSON, YAML, or TOML data, selected by format, and
+// normalizes the result to the same map[string]any / []any shape the rest
+// of voxgigstruct assumes of JSON-like data (YAML mapping nodes and TOML
+// tables are round-tripped through JSON so keys are always strings and
+// numbers are always float64, matching the approach ghodss/yaml uses).
+func Unmarshal(data []byte, format string) (any, error) {
+	switch strings.ToLower(format) {
+	case "", "json":
+		var out any
+		if err := json.Unmarshal(data, &out); err != nil {
+			return nil, err
+		}
+		return out, nil
+
+	case "yaml", "yml":
+		var raw any
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("voxgigstruct.Unmarshal: invalid yaml: %w", err)
+		}
+		return canonicalizeAny(raw)
+
+	case "toml":
+		var raw map[string]any
+		if err := toml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("voxgigstruct.Unmarshal: invalid toml: %w", err)
+		}
+		return canonicalizeAny(raw)
+
+	default:
+		return nil, fmt.Errorf("voxgigstruct.Unmarshal: unknown format %q", format)
+	}
+}
+
+// canonicalizeAny round-trips a decoded YAML/TOML value through JSON so it
+// matches what encoding/json would have produced directly.
+func canonicalizeAny(raw any) (any, error) {
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("voxgigstruct: cannot canonicalize: %w", err)
+	}
+	var out any
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// stringifyFormatOptions configures StringifyFormat.
+type stringifyFormatOptions struct {
+	format string
+	indent int
+}
+
+// StringifyFormatOption configures StringifyFormat; see WithFormat and
+// WithIndent.
+type StringifyFormatOption func(*stringifyFormatOptions)
+
+// WithFormat selects the output format for StringifyFormat: "json"
+// (default), "yaml", or "toml".
+func WithFormat(format string) StringifyFormatOption {
+	return func(o *stringifyFormatOptions) {
+		o.format = format
+	}
+}
+
+// WithIndent sets the indent width, in spaces, used for "json" and "yaml"
+// output. Ignored for "toml", which BurntSushi/toml always indents by one
+// tab per nesting level.
+func WithIndent(n int) StringifyFormatOption {
+	return func(o *stringifyFormatOptions) {
+		o.indent = n
+	}
+}
+
+// StringifyFormat renders v as pretty-printed JSON, YAML, or TOML,
+// according to opts. Unlike Stringify (which produces a short,
+// human-readable single-line summary for logging), StringifyFormat
+// produces a complete, parseable document suitable for config files.
+func StringifyFormat(v any, opts ...StringifyFormatOption) (string, error) {
+	o := stringifyFormatOptions{format: "json", indent: 2}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	switch strings.ToLower(o.format) {
+	case "", "json":
+		b, err := json.MarshalIndent(v, "", strings.Repeat(" ", o.indent))
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+
+	case "yaml", "yml":
+		var buf bytes.Buffer
+		enc := yaml.NewEncoder(&buf)
+		enc.SetIndent(o.indent)
+		if err := enc.Encode(v); err != nil {
+			return "", err
+		}
+		_ = enc.Close()
+		return buf.String(), nil
+
+	default:
+		b, err := Marshal(v, o.format)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+}
@@ -0,0 +1,45 @@
+package voxgigstruct_test
+
+import (
+	"reflect"
+	"testing"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+func TestMarshalUnmarshalJSONYAMLParity(t *testing.T) {
+	v := map[string]any{
+		"a": float64(1),
+		"b": []any{"x", "y"},
+	}
+
+	for _, format := range []string{"json", "yaml"} {
+		data, err := voxgigstruct.Marshal(v, format)
+		if err != nil {
+			t.Fatalf("marshal %s: %v", format, err)
+		}
+		out, err := voxgigstruct.Unmarshal(data, format)
+		if err != nil {
+			t.Fatalf("unmarshal %s: %v", format, err)
+		}
+		if !reflect.DeepEqual(v, out) {
+			t.Fatalf("%s round-trip mismatch: %+v != %+v", format, v, out)
+		}
+	}
+}
+
+func TestStringifyFormatIndentsJSON(t *testing.T) {
+	out, err := voxgigstruct.StringifyFormat(map[string]any{"a": float64(1)}, voxgigstruct.WithFormat("json"), voxgigstruct.WithIndent(4))
+	if err != nil {
+		t.Fatalf("StringifyFormat: %v", err)
+	}
+	if out != "{\n    \"a\": 1\n}" {
+		t.Fatalf("unexpected indented output: %q", out)
+	}
+}
+
+func TestUnmarshalUnknownFormatErrors(t *testing.T) {
+	if _, err := voxgigstruct.Unmarshal([]byte("x"), "ini"); err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+}
@@ -0,0 +1,316 @@
+package voxgigstruct
+
+import (
+	"sort"
+)
+
+// _collectSource resolves the source-path argument shared by $FILTER,
+// $SORT, $GROUP, $REDUCE and $FLATTEN into a []any, the same way
+// Transform_EACH resolves its own source-path: against state.Base within
+// store, falling back to nil for anything that isn't a list or map.
+func _collectSource(srcpath any, store any, current any, state *Injection) []any {
+	srcstore := GetProp(store, state.Base, store)
+	src := GetPathState(srcpath, srcstore, current, nil)
+
+	if srcpathStr, ok := srcpath.(string); ok {
+		recordTaint(state, taintLabelsFor(state, srcpathStr))
+	}
+
+	if IsList(src) {
+		if list, ok := src.([]any); ok {
+			return list
+		}
+		return _listify(src)
+	}
+
+	if IsMap(src) {
+		items := Items(src)
+		list := make([]any, len(items))
+		for i, item := range items {
+			list[i] = item[1]
+		}
+		return list
+	}
+
+	return nil
+}
+
+// _injectScope clones spec and injects it with scope bound as $TOP - the
+// same per-element context Transform_EACH builds for its child template,
+// reused here so predicate-spec/key-spec/step-spec arguments can themselves
+// use $COPY, $MERGE etc and so nested transforms compose. The store's own
+// $TOP is shadowed by scope (rather than left as the overall input data),
+// so a bare reference like '`age`' resolves against scope even when it is
+// the entire spec, not just a value nested one level down.
+func _injectScope(spec any, store any, modify Modify, scope any) any {
+	scopeStore := map[string]any{}
+	if sm, ok := store.(map[string]any); ok {
+		for k, v := range sm {
+			scopeStore[k] = v
+		}
+	}
+	scopeStore[S_DTOP] = scope
+
+	tcur := map[string]any{S_DTOP: scope}
+	return InjectDescend(Clone(spec), scopeStore, modify, tcur, nil)
+}
+
+// _writeCollectionResult replaces the value at the grandparent of a
+// ['$FOO', ...] directive list with result: the directive list itself
+// (state.Parent) is discarded wholesale, so unlike Transform_EACH there
+// is no list-index self-reference to keep stable, and result can be a
+// list, a map or a scalar.
+//
+// InjectDescend still runs its own generic write-back (_setParentProp)
+// against state.Parent/state.Key once this injector returns, to keep
+// list references consistent up the tree the way every other injector
+// relies on. Left alone, that write-back would clobber the direct write
+// above with the stale, unprocessed directive list. So, the same way
+// Transform_EACH repoints state.Parent at its replacement value, this
+// points state.Parent at result and returns the value already sitting
+// at state.Key within it - making that write-back a no-op instead of a
+// second, stale write.
+func _writeCollectionResult(state *Injection, result any) any {
+	tkey := S_MT
+	if len(state.Path) >= 2 {
+		tkey = state.Path[len(state.Path)-2]
+	}
+
+	var target any
+	if len(state.Nodes) >= 2 {
+		target = state.Nodes[len(state.Nodes)-2]
+	} else if len(state.Nodes) > 0 {
+		target = state.Nodes[len(state.Nodes)-1]
+	}
+
+	SetProp(target, tkey, result)
+
+	state.Parent = result
+	return GetProp(result, state.Key)
+}
+
+// isTruthyResult reports whether an injected predicate result counts as
+// truthy for $FILTER: anything other than nil, false, "" or an empty list
+// or map.
+func isTruthyResult(val any) bool {
+	switch v := val.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != S_MT
+	case []any:
+		return len(v) > 0
+	case map[string]any:
+		return len(v) > 0
+	default:
+		return true
+	}
+}
+
+// _compareSortKeys orders two $SORT keys: numerically if both coerce to a
+// number, lexically by string value otherwise.
+func _compareSortKeys(a, b any) bool {
+	if af, aerr := _toFloat64(a); aerr == nil {
+		if bf, berr := _toFloat64(b); berr == nil {
+			return af < bf
+		}
+	}
+	return _stringifyValue(a) < _stringifyValue(b)
+}
+
+// Keep list elements whose predicate-spec evaluates truthy when injected
+// against that element as $TOP.
+// Format: ['`$FILTER`', '`source-path`', predicate-spec]
+var Transform_FILTER Injector = func(
+	state *Injection,
+	val any,
+	current any,
+	ref *string,
+	store any,
+) any {
+	if nil != state.Keys {
+		state.Keys = state.Keys[:1]
+	}
+
+	if S_MVAL != state.Mode {
+		return nil
+	}
+
+	srcpath := GetProp(state.Parent, 1)
+	predSpec := GetProp(state.Parent, 2)
+	srclist := _collectSource(srcpath, store, current, state)
+
+	tval := make([]any, 0, len(srclist))
+	for _, item := range srclist {
+		if isTruthyResult(_injectScope(predSpec, store, state.Modify, item)) {
+			tval = append(tval, item)
+		}
+	}
+
+	return _writeCollectionResult(state, tval)
+}
+
+// Stable-sort list elements by a key extracted via key-spec, injected
+// against each element as $TOP.
+// Format: ['`$SORT`', '`source-path`', key-spec, 'asc'|'desc']
+var Transform_SORT Injector = func(
+	state *Injection,
+	val any,
+	current any,
+	ref *string,
+	store any,
+) any {
+	if nil != state.Keys {
+		state.Keys = state.Keys[:1]
+	}
+
+	if S_MVAL != state.Mode {
+		return nil
+	}
+
+	srcpath := GetProp(state.Parent, 1)
+	keySpec := GetProp(state.Parent, 2)
+	order, _ := GetProp(state.Parent, 3).(string)
+	srclist := _collectSource(srcpath, store, current, state)
+
+	type sortEntry struct {
+		key  any
+		item any
+	}
+	entries := make([]sortEntry, len(srclist))
+	for i, item := range srclist {
+		entries[i] = sortEntry{key: _injectScope(keySpec, store, state.Modify, item), item: item}
+	}
+
+	desc := order == "desc"
+	sort.SliceStable(entries, func(i, j int) bool {
+		if desc {
+			return _compareSortKeys(entries[j].key, entries[i].key)
+		}
+		return _compareSortKeys(entries[i].key, entries[j].key)
+	})
+
+	tval := make([]any, len(entries))
+	for i, e := range entries {
+		tval[i] = e.item
+	}
+
+	return _writeCollectionResult(state, tval)
+}
+
+// Group list elements by a key extracted via key-spec, injected against
+// each element as $TOP.
+// Format: ['`$GROUP`', '`source-path`', key-spec]
+var Transform_GROUP Injector = func(
+	state *Injection,
+	val any,
+	current any,
+	ref *string,
+	store any,
+) any {
+	if nil != state.Keys {
+		state.Keys = state.Keys[:1]
+	}
+
+	if S_MVAL != state.Mode {
+		return nil
+	}
+
+	srcpath := GetProp(state.Parent, 1)
+	keySpec := GetProp(state.Parent, 2)
+	srclist := _collectSource(srcpath, store, current, state)
+
+	tval := map[string]any{}
+	for _, item := range srclist {
+		k := _stringifyValue(_injectScope(keySpec, store, state.Modify, item))
+		group, _ := tval[k].([]any)
+		tval[k] = append(group, item)
+	}
+
+	return _writeCollectionResult(state, tval)
+}
+
+// Fold list elements left-to-right. Each step injects step-spec with
+// {$ACC, $CUR, $KEY} bound as $TOP, the accumulator starting from
+// init-spec injected the same way against the empty source.
+// Format: ['`$REDUCE`', '`source-path`', init-spec, step-spec]
+var Transform_REDUCE Injector = func(
+	state *Injection,
+	val any,
+	current any,
+	ref *string,
+	store any,
+) any {
+	if nil != state.Keys {
+		state.Keys = state.Keys[:1]
+	}
+
+	if S_MVAL != state.Mode {
+		return nil
+	}
+
+	srcpath := GetProp(state.Parent, 1)
+	initSpec := GetProp(state.Parent, 2)
+	stepSpec := GetProp(state.Parent, 3)
+	srclist := _collectSource(srcpath, store, current, state)
+
+	acc := _injectScope(initSpec, store, state.Modify, nil)
+	for i, item := range srclist {
+		acc = _injectScope(stepSpec, store, state.Modify, map[string]any{
+			"$ACC": acc,
+			"$CUR": item,
+			"$KEY": i,
+		})
+	}
+
+	return _writeCollectionResult(state, acc)
+}
+
+// Flatten nested lists to the given depth (default 1).
+// Format: ['`$FLATTEN`', '`source-path`', depth]
+var Transform_FLATTEN Injector = func(
+	state *Injection,
+	val any,
+	current any,
+	ref *string,
+	store any,
+) any {
+	if nil != state.Keys {
+		state.Keys = state.Keys[:1]
+	}
+
+	if S_MVAL != state.Mode {
+		return nil
+	}
+
+	srcpath := GetProp(state.Parent, 1)
+	depth := 1
+	if df, err := _toFloat64(GetProp(state.Parent, 2)); err == nil {
+		depth = int(df)
+	}
+	srclist := _collectSource(srcpath, store, current, state)
+
+	return _writeCollectionResult(state, _flattenList(srclist, depth))
+}
+
+func _flattenList(list []any, depth int) []any {
+	if depth <= 0 {
+		return list
+	}
+
+	out := make([]any, 0, len(list))
+	for _, item := range list {
+		if IsList(item) {
+			child, ok := item.([]any)
+			if !ok {
+				child = _listify(item)
+			}
+			out = append(out, _flattenList(child, depth-1)...)
+		} else {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -0,0 +1,187 @@
+package voxgigstruct_test
+
+import (
+	"reflect"
+	"testing"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+func TestTransformFilterKeepsTruthyElements(t *testing.T) {
+	data := map[string]any{
+		"users": []any{
+			map[string]any{"name": "Ada", "active": true},
+			map[string]any{"name": "Bea", "active": false},
+			map[string]any{"name": "Cel", "active": true},
+		},
+	}
+	spec := map[string]any{
+		"response": []any{"`$FILTER`", "users", "`active`"},
+	}
+
+	out := voxgigstruct.Transform(data, spec)
+
+	response, ok := voxgigstruct.GetPath("response", out).([]any)
+	if !ok || len(response) != 2 {
+		t.Fatalf("expected 2 active users, got %+v", out)
+	}
+
+	names := make([]any, len(response))
+	for i, u := range response {
+		names[i] = voxgigstruct.GetPath("name", u)
+	}
+	if !reflect.DeepEqual(names, []any{"Ada", "Cel"}) {
+		t.Fatalf("expected only the active users to survive filtering, got %+v", names)
+	}
+}
+
+func TestTransformSortOrdersByKeySpec(t *testing.T) {
+	data := map[string]any{
+		"users": []any{
+			map[string]any{"name": "Bea", "age": float64(30)},
+			map[string]any{"name": "Ada", "age": float64(20)},
+			map[string]any{"name": "Cel", "age": float64(25)},
+		},
+	}
+	spec := map[string]any{
+		"response": []any{"`$SORT`", "users", "`age`", "asc"},
+	}
+
+	out := voxgigstruct.Transform(data, spec)
+
+	response, ok := voxgigstruct.GetPath("response", out).([]any)
+	if !ok || len(response) != 3 {
+		t.Fatalf("expected 3 sorted users, got %+v", out)
+	}
+
+	names := make([]any, len(response))
+	for i, u := range response {
+		names[i] = voxgigstruct.GetPath("name", u)
+	}
+	if !reflect.DeepEqual(names, []any{"Ada", "Cel", "Bea"}) {
+		t.Fatalf("expected ascending age order, got %+v", names)
+	}
+}
+
+func TestTransformSortDescending(t *testing.T) {
+	data := map[string]any{
+		"users": []any{
+			map[string]any{"name": "Bea", "age": float64(30)},
+			map[string]any{"name": "Ada", "age": float64(20)},
+		},
+	}
+	spec := map[string]any{
+		"response": []any{"`$SORT`", "users", "`age`", "desc"},
+	}
+
+	out := voxgigstruct.Transform(data, spec)
+
+	response, _ := voxgigstruct.GetPath("response", out).([]any)
+	if voxgigstruct.GetPath("name", response[0]) != "Bea" {
+		t.Fatalf("expected Bea first in descending order, got %+v", response)
+	}
+}
+
+func TestTransformGroupBucketsByKeySpec(t *testing.T) {
+	data := map[string]any{
+		"users": []any{
+			map[string]any{"name": "Ada", "dept": "eng"},
+			map[string]any{"name": "Bea", "dept": "sales"},
+			map[string]any{"name": "Cel", "dept": "eng"},
+		},
+	}
+	spec := map[string]any{
+		"response": []any{"`$GROUP`", "users", "`dept`"},
+	}
+
+	out := voxgigstruct.Transform(data, spec)
+
+	response, ok := voxgigstruct.GetPath("response", out).(map[string]any)
+	if !ok {
+		t.Fatalf("expected response to be a map, got %+v", out)
+	}
+
+	eng, ok := response["eng"].([]any)
+	if !ok || len(eng) != 2 {
+		t.Fatalf("expected 2 users grouped under eng, got %+v", response)
+	}
+	sales, ok := response["sales"].([]any)
+	if !ok || len(sales) != 1 {
+		t.Fatalf("expected 1 user grouped under sales, got %+v", response)
+	}
+}
+
+func TestTransformReduceFoldsElements(t *testing.T) {
+	data := map[string]any{
+		"names": []any{"Ada", "Bea", "Cel"},
+	}
+	spec := map[string]any{
+		// step-spec ignores $ACC and keeps taking $CUR, so the fold's
+		// result is simply the last element injected.
+		"last": []any{"`$REDUCE`", "names", "", "`$CUR`"},
+	}
+
+	out := voxgigstruct.Transform(data, spec)
+
+	if last := voxgigstruct.GetPath("last", out); last != "Cel" {
+		t.Fatalf("expected the fold to end on the last element, got %+v", last)
+	}
+}
+
+func TestTransformFlattenRespectsDepth(t *testing.T) {
+	data := map[string]any{
+		"nested": []any{
+			[]any{float64(1), float64(2)},
+			[]any{float64(3), []any{float64(4), float64(5)}},
+		},
+	}
+
+	out1 := voxgigstruct.Transform(data, map[string]any{
+		"flat": []any{"`$FLATTEN`", "nested", float64(1)},
+	})
+	flat1, ok := voxgigstruct.GetPath("flat", out1).([]any)
+	if !ok || len(flat1) != 4 {
+		t.Fatalf("expected depth-1 flatten to unwrap one level, got %+v", flat1)
+	}
+	if inner, ok := flat1[3].([]any); !ok || len(inner) != 2 {
+		t.Fatalf("expected depth-1 flatten to leave the doubly-nested list intact, got %+v", flat1)
+	}
+
+	out2 := voxgigstruct.Transform(data, map[string]any{
+		"flat": []any{"`$FLATTEN`", "nested", float64(2)},
+	})
+	flat2, ok := voxgigstruct.GetPath("flat", out2).([]any)
+	if !ok || len(flat2) != 5 {
+		t.Fatalf("expected depth-2 flatten to fully flatten, got %+v", flat2)
+	}
+}
+
+// $SORT operating on $FILTER's output: the two transforms are independent
+// (each resolves its own source-path against whatever data it is handed),
+// so composing them is done the same way any two Transform stages compose
+// - by feeding one's result as the next's input.
+func TestTransformFilterThenSortCompose(t *testing.T) {
+	data := map[string]any{
+		"users": []any{
+			map[string]any{"name": "Ada", "active": true, "age": float64(40)},
+			map[string]any{"name": "Bea", "active": false, "age": float64(20)},
+			map[string]any{"name": "Cel", "active": true, "age": float64(25)},
+		},
+	}
+
+	filtered := voxgigstruct.Transform(data, map[string]any{
+		"users": []any{"`$FILTER`", "users", "`active`"},
+	})
+
+	sorted := voxgigstruct.Transform(filtered, map[string]any{
+		"users": []any{"`$SORT`", "users", "`age`", "asc"},
+	})
+
+	byAge, ok := voxgigstruct.GetPath("users", sorted).([]any)
+	if !ok || len(byAge) != 2 {
+		t.Fatalf("expected 2 active users sorted by age, got %+v", sorted)
+	}
+	if voxgigstruct.GetPath("name", byAge[0]) != "Cel" {
+		t.Fatalf("expected Cel (25) before Ada (40), got %+v", byAge)
+	}
+}
@@ -0,0 +1,442 @@
+package voxgigstruct
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Constraint is the AST for the CUE-inspired constraint DSL used by schema
+// strings such as "int & >=0 & <=100", `string & =~"^[a-z]+$"`, or
+// `"red" | "green" | "blue"`. It is parsed by ParseConstraint, combined by
+// Unify (lattice meet), and checked against a value by Check.
+type Constraint struct {
+	Kind ConstraintKind
+
+	// CKType
+	Type string
+
+	// CKRange
+	Min, Max           *float64
+	MinExcl, MaxExcl   bool
+
+	// CKRegex
+	Pattern string
+	re      *regexp.Regexp
+
+	// CKValue
+	Value any
+
+	// CKAnd, CKOr
+	Parts []*Constraint
+
+	// CKNot
+	Inner *Constraint
+
+	// CKBottom
+	Reason string
+}
+
+type ConstraintKind int
+
+const (
+	CKType ConstraintKind = iota
+	CKRange
+	CKRegex
+	CKValue
+	CKAnd
+	CKOr
+	CKNot
+	CKBottom
+)
+
+var constraintTypeNames = map[string]bool{
+	S_string: true, S_number: true, S_boolean: true, S_object: true, S_array: true, "any": true, "int": true,
+}
+
+// Bottom builds an inconsistent constraint carrying a human-readable reason.
+func Bottom(reason string) *Constraint {
+	return &Constraint{Kind: CKBottom, Reason: reason}
+}
+
+// looksLikeConstraint reports whether a backtick reference should be parsed
+// as a Constraint DSL expression, rather than resolved as a plain GetPath
+// reference or `$NAME` shorthand.
+func looksLikeConstraint(ref string) bool {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return false
+	}
+	if strings.ContainsAny(ref, "&|") || strings.Contains(ref, "=~") {
+		return true
+	}
+	if ref[0] == '"' || ref[0] == '<' || ref[0] == '>' || ref[0] == '=' || ref[0] == '!' {
+		return true
+	}
+	word := ref
+	if i := strings.IndexAny(ref, " \t"); i >= 0 {
+		word = ref[:i]
+	}
+	return constraintTypeNames[word]
+}
+
+// ParseConstraint parses the constraint DSL: a "|"-separated disjunction of
+// "&"-separated conjunctions of atoms (bare types, comparisons, regexes, and
+// literal values).
+func ParseConstraint(src string) (*Constraint, error) {
+	branches := splitTop(src, '|')
+	var ors []*Constraint
+	for _, branch := range branches {
+		c, err := parseAnd(branch)
+		if err != nil {
+			return nil, err
+		}
+		ors = append(ors, c)
+	}
+	if len(ors) == 1 {
+		return ors[0], nil
+	}
+	return &Constraint{Kind: CKOr, Parts: ors}, nil
+}
+
+func parseAnd(src string) (*Constraint, error) {
+	atoms := splitTop(src, '&')
+	var ands []*Constraint
+	for _, atom := range atoms {
+		c, err := parseAtom(atom)
+		if err != nil {
+			return nil, err
+		}
+		ands = append(ands, c)
+	}
+	if len(ands) == 1 {
+		return ands[0], nil
+	}
+	return &Constraint{Kind: CKAnd, Parts: ands}, nil
+}
+
+var cmpRe = regexp.MustCompile(`^(>=|<=|==|!=|>|<)\s*(-?[0-9]+(?:\.[0-9]+)?)$`)
+
+func parseAtom(src string) (*Constraint, error) {
+	s := strings.TrimSpace(src)
+	if s == "" {
+		return nil, fmt.Errorf("constraint: empty atom")
+	}
+
+	if constraintTypeNames[s] {
+		return &Constraint{Kind: CKType, Type: s}, nil
+	}
+
+	if strings.HasPrefix(s, "=~") {
+		pattern := strings.TrimSpace(strings.TrimPrefix(s, "=~"))
+		pattern = strings.Trim(pattern, "\"")
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("constraint: invalid regex %q: %w", pattern, err)
+		}
+		return &Constraint{Kind: CKRegex, Pattern: pattern, re: re}, nil
+	}
+
+	if m := cmpRe.FindStringSubmatch(s); m != nil {
+		n, err := strconv.ParseFloat(m[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("constraint: invalid number %q", m[2])
+		}
+		c := &Constraint{Kind: CKRange}
+		switch m[1] {
+		case ">=":
+			c.Min = &n
+		case ">":
+			c.Min = &n
+			c.MinExcl = true
+		case "<=":
+			c.Max = &n
+		case "<":
+			c.Max = &n
+			c.MaxExcl = true
+		case "==":
+			c.Min, c.Max = &n, &n
+		case "!=":
+			return &Constraint{Kind: CKNot, Inner: &Constraint{Kind: CKValue, Value: n}}, nil
+		}
+		return c, nil
+	}
+
+	if strings.HasPrefix(s, "\"") && strings.HasSuffix(s, "\"") && len(s) >= 2 {
+		return &Constraint{Kind: CKValue, Value: strings.Trim(s, "\"")}, nil
+	}
+
+	if s == "true" || s == "false" {
+		return &Constraint{Kind: CKValue, Value: s == "true"}, nil
+	}
+
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		return &Constraint{Kind: CKValue, Value: n}, nil
+	}
+
+	return nil, fmt.Errorf("constraint: cannot parse atom %q", s)
+}
+
+// splitTop splits src on sep, but only at the top level: inside a "..."
+// literal the separator is not treated as a split point.
+func splitTop(src string, sep rune) []string {
+	var parts []string
+	var cur strings.Builder
+	inStr := false
+	for _, r := range src {
+		switch {
+		case r == '"':
+			inStr = !inStr
+			cur.WriteRune(r)
+		case r == sep && !inStr:
+			parts = append(parts, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	parts = append(parts, cur.String())
+	return parts
+}
+
+// Check tests whether value satisfies the constraint, returning a
+// human-readable reason on failure.
+func Check(value any, c *Constraint) (bool, string) {
+	if c == nil {
+		return true, ""
+	}
+
+	switch c.Kind {
+	case CKBottom:
+		return false, c.Reason
+
+	case CKType:
+		return checkType(value, c.Type)
+
+	case CKRange:
+		n, ok := value.(float64)
+		if !ok {
+			return false, fmt.Sprintf("expected a number, got %s", Typify(value))
+		}
+		if c.Min != nil {
+			if c.MinExcl && !(n > *c.Min) {
+				return false, fmt.Sprintf("%v is not > %v", n, *c.Min)
+			}
+			if !c.MinExcl && !(n >= *c.Min) {
+				return false, fmt.Sprintf("%v is not >= %v", n, *c.Min)
+			}
+		}
+		if c.Max != nil {
+			if c.MaxExcl && !(n < *c.Max) {
+				return false, fmt.Sprintf("%v is not < %v", n, *c.Max)
+			}
+			if !c.MaxExcl && !(n <= *c.Max) {
+				return false, fmt.Sprintf("%v is not <= %v", n, *c.Max)
+			}
+		}
+		return true, ""
+
+	case CKRegex:
+		s, ok := value.(string)
+		if !ok {
+			return false, fmt.Sprintf("expected a string, got %s", Typify(value))
+		}
+		if !c.re.MatchString(s) {
+			return false, fmt.Sprintf("%q does not match /%s/", s, c.Pattern)
+		}
+		return true, ""
+
+	case CKValue:
+		if value == c.Value {
+			return true, ""
+		}
+		return false, fmt.Sprintf("%v is not equal to %v", value, c.Value)
+
+	case CKAnd:
+		for _, p := range c.Parts {
+			if ok, reason := Check(value, p); !ok {
+				return false, reason
+			}
+		}
+		return true, ""
+
+	case CKOr:
+		var reasons []string
+		for _, p := range c.Parts {
+			if ok, _ := Check(value, p); ok {
+				return true, ""
+			} else {
+				_, reason := Check(value, p)
+				reasons = append(reasons, reason)
+			}
+		}
+		return false, "none of: " + strings.Join(reasons, "; ")
+
+	case CKNot:
+		ok, _ := Check(value, c.Inner)
+		if ok {
+			return false, fmt.Sprintf("value must not satisfy %v", c.Inner)
+		}
+		return true, ""
+	}
+
+	return false, "unknown constraint kind"
+}
+
+func checkType(value any, typeName string) (bool, string) {
+	t := Typify(value)
+	switch typeName {
+	case "any":
+		return true, ""
+	case "int":
+		n, ok := value.(float64)
+		if !ok || n != float64(int64(n)) {
+			return false, fmt.Sprintf("expected an int, got %s", t)
+		}
+		return true, ""
+	default:
+		if t != typeName {
+			return false, fmt.Sprintf("expected %s, got %s", typeName, t)
+		}
+		return true, ""
+	}
+}
+
+// Unify computes the lattice meet (greatest-lower-bound) of two
+// constraints: the narrowest constraint satisfying both. Incompatible
+// constraints unify to Bottom, carrying the reason.
+func Unify(a, b *Constraint) *Constraint {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.Kind == CKBottom {
+		return a
+	}
+	if b.Kind == CKBottom {
+		return b
+	}
+
+	if a.Kind == CKOr {
+		return unifyOr(a, b)
+	}
+	if b.Kind == CKOr {
+		return unifyOr(b, a)
+	}
+
+	if a.Kind == CKType && b.Kind == CKType {
+		if a.Type == b.Type {
+			return a
+		}
+		return Bottom(fmt.Sprintf("incompatible types %s and %s", a.Type, b.Type))
+	}
+
+	if a.Kind == CKRange && b.Kind == CKRange {
+		return unifyRange(a, b)
+	}
+
+	if a.Kind == CKValue && b.Kind == CKValue {
+		if a.Value == b.Value {
+			return a
+		}
+		return Bottom(fmt.Sprintf("incompatible values %v and %v", a.Value, b.Value))
+	}
+
+	parts := flattenAnd(a)
+	parts = append(parts, flattenAnd(b)...)
+	return &Constraint{Kind: CKAnd, Parts: parts}
+}
+
+func flattenAnd(c *Constraint) []*Constraint {
+	if c.Kind == CKAnd {
+		return append([]*Constraint{}, c.Parts...)
+	}
+	return []*Constraint{c}
+}
+
+func unifyRange(a, b *Constraint) *Constraint {
+	out := &Constraint{Kind: CKRange}
+
+	out.Min, out.MinExcl = tighterMin(a.Min, a.MinExcl, b.Min, b.MinExcl)
+	out.Max, out.MaxExcl = tighterMax(a.Max, a.MaxExcl, b.Max, b.MaxExcl)
+
+	if out.Min != nil && out.Max != nil && *out.Min > *out.Max {
+		return Bottom(fmt.Sprintf("empty range: %v..%v", *out.Min, *out.Max))
+	}
+	return out
+}
+
+func tighterMin(am *float64, aExcl bool, bm *float64, bExcl bool) (*float64, bool) {
+	if am == nil {
+		return bm, bExcl
+	}
+	if bm == nil {
+		return am, aExcl
+	}
+	if *am > *bm {
+		return am, aExcl
+	}
+	if *bm > *am {
+		return bm, bExcl
+	}
+	return am, aExcl || bExcl
+}
+
+func tighterMax(am *float64, aExcl bool, bm *float64, bExcl bool) (*float64, bool) {
+	if am == nil {
+		return bm, bExcl
+	}
+	if bm == nil {
+		return am, aExcl
+	}
+	if *am < *bm {
+		return am, aExcl
+	}
+	if *bm < *am {
+		return bm, bExcl
+	}
+	return am, aExcl || bExcl
+}
+
+// unifyOr unifies a disjunction against another constraint by keeping only
+// the branches of or that remain consistent with other, collapsing to a
+// single Constraint when only one survives.
+func unifyOr(or *Constraint, other *Constraint) *Constraint {
+	var kept []*Constraint
+	for _, p := range or.Parts {
+		u := Unify(p, other)
+		if u.Kind != CKBottom {
+			kept = append(kept, u)
+		}
+	}
+	if len(kept) == 0 {
+		return Bottom("no branch of the disjunction is compatible")
+	}
+	if len(kept) == 1 {
+		return kept[0]
+	}
+	return &Constraint{Kind: CKOr, Parts: kept}
+}
+
+// validateConstraint is the Injector wired into the Validate/ValidateCollect
+// store for any backtick schema string recognised by looksLikeConstraint.
+func validateConstraint(src string) Injector {
+	return func(state *Injection, _val any, current any, ref *string, store any) any {
+		c, err := ParseConstraint(src)
+		if err != nil {
+			state.addErr(fmt.Sprintf("%s: %s", Pathify(state.Path, 0), err.Error()))
+			return nil
+		}
+
+		out := GetProp(current, state.Key)
+		if ok, reason := Check(out, c); !ok {
+			state.addErr(fmt.Sprintf("%s: %s", Pathify(state.Path, 0), reason))
+			return nil
+		}
+
+		return out
+	}
+}
@@ -0,0 +1,117 @@
+package voxgigstruct_test
+
+import (
+	"testing"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+func TestConstraintRange(t *testing.T) {
+	c, err := voxgigstruct.ParseConstraint("int & >=0 & <=100")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if ok, _ := voxgigstruct.Check(float64(50), c); !ok {
+		t.Fatal("expected 50 to satisfy int & >=0 & <=100")
+	}
+	if ok, _ := voxgigstruct.Check(float64(150), c); ok {
+		t.Fatal("expected 150 to fail int & >=0 & <=100")
+	}
+}
+
+func TestConstraintRegex(t *testing.T) {
+	c, err := voxgigstruct.ParseConstraint(`string & =~"^[a-z]+$"`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if ok, _ := voxgigstruct.Check("abc", c); !ok {
+		t.Fatal("expected abc to match")
+	}
+	if ok, _ := voxgigstruct.Check("ABC", c); ok {
+		t.Fatal("expected ABC to fail")
+	}
+}
+
+func TestConstraintEnum(t *testing.T) {
+	c, err := voxgigstruct.ParseConstraint(`"red" | "green" | "blue"`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if ok, _ := voxgigstruct.Check("green", c); !ok {
+		t.Fatal("expected green to satisfy enum")
+	}
+	if ok, _ := voxgigstruct.Check("yellow", c); ok {
+		t.Fatal("expected yellow to fail enum")
+	}
+}
+
+func TestUnifyRangeNarrows(t *testing.T) {
+	a, _ := voxgigstruct.ParseConstraint("int & >0")
+	b, _ := voxgigstruct.ParseConstraint("int & <=100")
+	u := voxgigstruct.Unify(a, b)
+
+	if ok, _ := voxgigstruct.Check(float64(50), u); !ok {
+		t.Fatal("expected 50 to satisfy the unified range")
+	}
+	if ok, _ := voxgigstruct.Check(float64(0), u); ok {
+		t.Fatal("expected 0 to fail the unified range (exclusive >0)")
+	}
+}
+
+func TestUnifyEnumCollapses(t *testing.T) {
+	a, _ := voxgigstruct.ParseConstraint(`"red" | "green"`)
+	b, _ := voxgigstruct.ParseConstraint(`"green" | "blue"`)
+	u := voxgigstruct.Unify(a, b)
+
+	if u.Kind != voxgigstruct.CKValue || u.Value != "green" {
+		t.Fatalf("expected unify to collapse to the single value green, got %+v", u)
+	}
+}
+
+func TestUnifyIncompatibleTypesBottom(t *testing.T) {
+	a, _ := voxgigstruct.ParseConstraint("int")
+	b, _ := voxgigstruct.ParseConstraint("string")
+	u := voxgigstruct.Unify(a, b)
+
+	if u.Kind != voxgigstruct.CKBottom {
+		t.Fatalf("expected Bottom, got %+v", u)
+	}
+}
+
+func TestValidateConstraintShorthand(t *testing.T) {
+	spec := map[string]any{
+		"age": "`int & >=0 & <=100`",
+	}
+	data := map[string]any{
+		"age": float64(30),
+	}
+
+	out, err := voxgigstruct.Validate(data, spec)
+	if err != nil {
+		t.Fatalf("expected valid data, got error: %v", err)
+	}
+	outMap := out.(map[string]any)
+	if outMap["age"] != float64(30) {
+		t.Fatalf("expected age 30, got %v", outMap["age"])
+	}
+
+	_, err = voxgigstruct.Validate(map[string]any{"age": float64(200)}, spec)
+	if err == nil {
+		t.Fatal("expected error for out-of-range age")
+	}
+}
+
+func TestValidateOptionalField(t *testing.T) {
+	spec := map[string]any{
+		"name":     "`$STRING`",
+		"nickname?": "`$STRING`",
+	}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"name": "Alex"}, spec); err != nil {
+		t.Fatalf("expected optional field to be skippable, got: %v", err)
+	}
+
+	if _, err := voxgigstruct.Validate(map[string]any{}, spec); err == nil {
+		t.Fatal("expected missing required field 'name' to fail")
+	}
+}
@@ -0,0 +1,68 @@
+package expr
+
+// node is the parsed AST for an expression, before compilation to bytecode.
+type node interface{}
+
+type numLit struct{ val float64 }
+type strLit struct{ val string }
+type boolLit struct{ val bool }
+type nullLit struct{}
+
+// ident is a bare name, resolved via GetPath against current/store.
+type ident struct{ name string }
+
+// field is a `.name` accessor on target.
+type field struct {
+	target node
+	name   string
+}
+
+// index is a `[expr]` accessor on target.
+type index struct {
+	target node
+	idx    node
+}
+
+// call is a function call, dispatched through the pluggable function table.
+type call struct {
+	name string
+	args []node
+}
+
+type unary struct {
+	op  string
+	val node
+}
+
+type binary struct {
+	op          string
+	left, right node
+}
+
+// ternary is the Python-style `then if cond else els` expression.
+type ternary struct {
+	cond, then, els node
+}
+
+type inExpr struct {
+	elem, list node
+}
+
+// letExpr is `let name = value in body`.
+type letExpr struct {
+	name  string
+	value node
+	body  node
+}
+
+// arrLit is an array literal, e.g. `[1, 2, a.b]`.
+type arrLit struct {
+	items []node
+}
+
+// objLit is a map literal, e.g. `{"a": 1, "b": x}`. Keys are string or
+// identifier tokens, evaluated as literal key names (not expressions).
+type objLit struct {
+	keys []string
+	vals []node
+}
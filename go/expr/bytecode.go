@@ -0,0 +1,45 @@
+package expr
+
+type opCode int
+
+const (
+	OpLoadConst opCode = iota
+	OpLoadPath         // push GetPath(path, current/store)
+	OpLoadLocal        // push a `let`-bound local
+	OpStoreLocal       // pop and bind a `let` local
+	OpField            // pop target, push target.name
+	OpIndex            // pop target, idx; push target[idx]
+	OpCall             // pop argc values, call named function, push result
+	OpAdd
+	OpSub
+	OpMul
+	OpDiv
+	OpMod
+	OpNeg
+	OpNot
+	OpEq
+	OpNeq
+	OpLt
+	OpLte
+	OpGt
+	OpGte
+	OpIn
+	OpJump       // unconditional jump to an absolute op index
+	OpJumpIfFalse // pop; jump if falsy
+	OpPop
+	OpMakeArray  // pop Argc values, push them as a []any (in source order)
+	OpMakeObject // pop Argc values, push them as a map[string]any keyed by op.Keys
+)
+
+// op is a single bytecode instruction. Which fields are meaningful depends
+// on Code; e.g. OpLoadConst uses Val, OpLoadPath uses Path, OpCall uses
+// Name+Argc, OpJump/OpJumpIfFalse use Addr.
+type op struct {
+	Code opCode
+	Val  any
+	Path []string
+	Name string
+	Argc int
+	Addr int
+	Keys []string // OpMakeObject's key names, in the same order as its values
+}
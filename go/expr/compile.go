@@ -0,0 +1,224 @@
+package expr
+
+import "fmt"
+
+// compiler turns an AST into a flat bytecode program.
+type compiler struct {
+	ops []op
+}
+
+func compile(n node) ([]op, error) {
+	c := &compiler{}
+	if err := c.emit(n); err != nil {
+		return nil, err
+	}
+	return c.ops, nil
+}
+
+func (c *compiler) add(o op) int {
+	c.ops = append(c.ops, o)
+	return len(c.ops) - 1
+}
+
+func (c *compiler) emit(n node) error {
+	switch v := n.(type) {
+	case *numLit:
+		c.add(op{Code: OpLoadConst, Val: v.val})
+
+	case *strLit:
+		c.add(op{Code: OpLoadConst, Val: v.val})
+
+	case *boolLit:
+		c.add(op{Code: OpLoadConst, Val: v.val})
+
+	case *nullLit:
+		c.add(op{Code: OpLoadConst, Val: nil})
+
+	case *ident:
+		c.add(op{Code: OpLoadPath, Path: []string{v.name}})
+
+	case *field:
+		if err := c.emit(v.target); err != nil {
+			return err
+		}
+		c.add(op{Code: OpField, Name: v.name})
+
+	case *index:
+		if err := c.emit(v.target); err != nil {
+			return err
+		}
+		if err := c.emit(v.idx); err != nil {
+			return err
+		}
+		c.add(op{Code: OpIndex})
+
+	case *call:
+		for _, a := range v.args {
+			if err := c.emit(a); err != nil {
+				return err
+			}
+		}
+		c.add(op{Code: OpCall, Name: v.name, Argc: len(v.args)})
+
+	case *unary:
+		if err := c.emit(v.val); err != nil {
+			return err
+		}
+		switch v.op {
+		case "-":
+			c.add(op{Code: OpNeg})
+		case "not":
+			c.add(op{Code: OpNot})
+		default:
+			return fmt.Errorf("expr: unknown unary operator %q", v.op)
+		}
+
+	case *binary:
+		return c.emitBinary(v)
+
+	case *ternary:
+		return c.emitTernary(v)
+
+	case *inExpr:
+		if err := c.emit(v.elem); err != nil {
+			return err
+		}
+		if err := c.emit(v.list); err != nil {
+			return err
+		}
+		c.add(op{Code: OpIn})
+
+	case *letExpr:
+		if err := c.emit(v.value); err != nil {
+			return err
+		}
+		c.add(op{Code: OpStoreLocal, Name: v.name})
+		if err := c.emit(v.body); err != nil {
+			return err
+		}
+
+	case *arrLit:
+		for _, item := range v.items {
+			if err := c.emit(item); err != nil {
+				return err
+			}
+		}
+		c.add(op{Code: OpMakeArray, Argc: len(v.items)})
+
+	case *objLit:
+		for _, val := range v.vals {
+			if err := c.emit(val); err != nil {
+				return err
+			}
+		}
+		c.add(op{Code: OpMakeObject, Argc: len(v.vals), Keys: v.keys})
+
+	default:
+		return fmt.Errorf("expr: unknown AST node %T", n)
+	}
+
+	return nil
+}
+
+func (c *compiler) emitBinary(v *binary) error {
+	switch v.op {
+	case "and":
+		return c.emitAnd(v)
+	case "or":
+		return c.emitOr(v)
+	}
+
+	if err := c.emit(v.left); err != nil {
+		return err
+	}
+	if err := c.emit(v.right); err != nil {
+		return err
+	}
+
+	switch v.op {
+	case "+":
+		c.add(op{Code: OpAdd})
+	case "-":
+		c.add(op{Code: OpSub})
+	case "*":
+		c.add(op{Code: OpMul})
+	case "/":
+		c.add(op{Code: OpDiv})
+	case "%":
+		c.add(op{Code: OpMod})
+	case "==":
+		c.add(op{Code: OpEq})
+	case "!=":
+		c.add(op{Code: OpNeq})
+	case "<":
+		c.add(op{Code: OpLt})
+	case "<=":
+		c.add(op{Code: OpLte})
+	case ">":
+		c.add(op{Code: OpGt})
+	case ">=":
+		c.add(op{Code: OpGte})
+	default:
+		return fmt.Errorf("expr: unknown binary operator %q", v.op)
+	}
+	return nil
+}
+
+// emitAnd short-circuits: if the left operand is falsy, the result is
+// false without evaluating right.
+func (c *compiler) emitAnd(v *binary) error {
+	if err := c.emit(v.left); err != nil {
+		return err
+	}
+	jumpFalse := c.add(op{Code: OpJumpIfFalse})
+	if err := c.emit(v.right); err != nil {
+		return err
+	}
+	jumpEnd := c.add(op{Code: OpJump})
+	c.ops[jumpFalse].Addr = len(c.ops)
+	c.add(op{Code: OpLoadConst, Val: false})
+	c.ops[jumpEnd].Addr = len(c.ops)
+	return nil
+}
+
+// emitOr short-circuits: if the left operand is truthy, the result is true
+// without evaluating right.
+func (c *compiler) emitOr(v *binary) error {
+	if err := c.emit(v.left); err != nil {
+		return err
+	}
+	jumpFalse := c.add(op{Code: OpJumpIfFalse})
+	c.add(op{Code: OpLoadConst, Val: true})
+	jumpEnd := c.add(op{Code: OpJump})
+	c.ops[jumpFalse].Addr = len(c.ops)
+	if err := c.emit(v.right); err != nil {
+		return err
+	}
+	c.ops[jumpEnd].Addr = len(c.ops)
+	return nil
+}
+
+// emitTernary compiles `then if cond else els` as:
+//
+//	<cond>
+//	JumpIfFalse L1
+//	<then>
+//	Jump L2
+//	L1: <els>
+//	L2:
+func (c *compiler) emitTernary(v *ternary) error {
+	if err := c.emit(v.cond); err != nil {
+		return err
+	}
+	jumpFalse := c.add(op{Code: OpJumpIfFalse})
+	if err := c.emit(v.then); err != nil {
+		return err
+	}
+	jumpEnd := c.add(op{Code: OpJump})
+	c.ops[jumpFalse].Addr = len(c.ops)
+	if err := c.emit(v.els); err != nil {
+		return err
+	}
+	c.ops[jumpEnd].Addr = len(c.ops)
+	return nil
+}
@@ -0,0 +1,149 @@
+package expr
+
+import "testing"
+
+func eval(t *testing.T, src string, ctx EvalContext) any {
+	t.Helper()
+	prog, err := Compile(src)
+	if err != nil {
+		t.Fatalf("compile %q: %v", src, err)
+	}
+	out, err := prog.Eval(ctx)
+	if err != nil {
+		t.Fatalf("eval %q: %v", src, err)
+	}
+	return out
+}
+
+func TestArithmeticPrecedence(t *testing.T) {
+	out := eval(t, "1 + 2 * 3", EvalContext{})
+	if out != float64(7) {
+		t.Fatalf("expected 7, got %v", out)
+	}
+}
+
+func TestStringConcat(t *testing.T) {
+	out := eval(t, `"a" + "b" + 1`, EvalContext{})
+	if out != "ab1" {
+		t.Fatalf("expected ab1, got %v", out)
+	}
+}
+
+func TestComparisonAndLogic(t *testing.T) {
+	out := eval(t, "1 < 2 and 2 < 3", EvalContext{})
+	if out != true {
+		t.Fatalf("expected true, got %v", out)
+	}
+}
+
+func TestTernary(t *testing.T) {
+	out := eval(t, `"yes" if 1 == 1 else "no"`, EvalContext{})
+	if out != "yes" {
+		t.Fatalf("expected yes, got %v", out)
+	}
+}
+
+func TestIn(t *testing.T) {
+	out := eval(t, `2 in [1, 2, 3]`, EvalContext{})
+	if out != true {
+		t.Fatalf("expected true, got %v", out)
+	}
+}
+
+func TestLetBinding(t *testing.T) {
+	out := eval(t, "let x = 2 + 3 in x * x", EvalContext{})
+	if out != float64(25) {
+		t.Fatalf("expected 25, got %v", out)
+	}
+}
+
+func TestIdentAndFieldAccess(t *testing.T) {
+	ctx := EvalContext{Current: map[string]any{"a": map[string]any{"b": float64(10)}}}
+	out := eval(t, "a.b + 1", ctx)
+	if out != float64(11) {
+		t.Fatalf("expected 11, got %v", out)
+	}
+}
+
+func TestIndexAccess(t *testing.T) {
+	ctx := EvalContext{Current: map[string]any{"list": []any{float64(1), float64(2), float64(3)}}}
+	out := eval(t, "list[1]", ctx)
+	if out != float64(2) {
+		t.Fatalf("expected 2, got %v", out)
+	}
+}
+
+func TestFunctionCall(t *testing.T) {
+	ctx := EvalContext{Funcs: map[string]Func{
+		"double": func(args ...any) (any, error) {
+			n, _ := toNumber(args[0])
+			return n * 2, nil
+		},
+	}}
+	out := eval(t, "double(21)", ctx)
+	if out != float64(42) {
+		t.Fatalf("expected 42, got %v", out)
+	}
+}
+
+func TestDivisionByZeroError(t *testing.T) {
+	prog, err := Compile("1 / 0")
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	if _, err := prog.Eval(EvalContext{Path: []string{"a", "b"}}); err == nil {
+		t.Fatal("expected division by zero error")
+	}
+}
+
+func TestArrayLiteral(t *testing.T) {
+	out := eval(t, `[1, 2, 1 + 2]`, EvalContext{})
+	list, ok := out.([]any)
+	if !ok || len(list) != 3 || list[2] != float64(3) {
+		t.Fatalf("expected [1 2 3], got %v", out)
+	}
+}
+
+func TestObjectLiteral(t *testing.T) {
+	out := eval(t, `{"a": 1, b: 2 + 2}`, EvalContext{})
+	obj, ok := out.(map[string]any)
+	if !ok || obj["a"] != float64(1) || obj["b"] != float64(4) {
+		t.Fatalf("expected {a:1 b:4}, got %v", out)
+	}
+}
+
+// TestShortCircuitAnd confirms the right operand of `and` is never
+// evaluated once the left is falsy - a function call on the right side
+// would error if it ran, since it's not registered.
+func TestShortCircuitAnd(t *testing.T) {
+	out := eval(t, "false and missing()", EvalContext{})
+	if out != false {
+		t.Fatalf("expected false, got %v", out)
+	}
+}
+
+// TestShortCircuitOr is the `or` analogue of TestShortCircuitAnd.
+func TestShortCircuitOr(t *testing.T) {
+	out := eval(t, "true or missing()", EvalContext{})
+	if out != true {
+		t.Fatalf("expected true, got %v", out)
+	}
+}
+
+func TestUnknownFunctionErrorCarriesPath(t *testing.T) {
+	prog, err := Compile("missing()")
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	_, err = prog.Eval(EvalContext{Path: []string{"x", "y"}})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	evalErr, ok := err.(*EvalError)
+	if !ok {
+		t.Fatalf("expected *EvalError, got %T", err)
+	}
+	if len(evalErr.Path) != 2 || evalErr.Path[1] != "y" {
+		t.Fatalf("expected path to be carried through, got %v", evalErr.Path)
+	}
+}
@@ -0,0 +1,136 @@
+/* Copyright (c) 2025 Voxgig Ltd. MIT LICENSE. */
+
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tEOF tokenKind = iota
+	tNumber
+	tString
+	tIdent
+	tKeyword
+	tPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	num  float64
+}
+
+var keywords = map[string]bool{
+	"if": true, "else": true, "let": true, "in": true,
+	"and": true, "or": true, "not": true,
+	"true": true, "false": true, "null": true,
+}
+
+// lexer tokenizes an expression source string.
+type lexer struct {
+	src []rune
+	pos int
+}
+
+func newLexer(src string) *lexer {
+	return &lexer{src: []rune(src)}
+}
+
+func (l *lexer) peekRune() rune {
+	if l.pos >= len(l.src) {
+		return 0
+	}
+	return l.src[l.pos]
+}
+
+func (l *lexer) tokens() ([]token, error) {
+	var out []token
+	for {
+		tok, err := l.next()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, tok)
+		if tok.kind == tEOF {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (l *lexer) next() (token, error) {
+	for l.pos < len(l.src) && unicode.IsSpace(l.src[l.pos]) {
+		l.pos++
+	}
+
+	if l.pos >= len(l.src) {
+		return token{kind: tEOF}, nil
+	}
+
+	c := l.src[l.pos]
+
+	switch {
+	case unicode.IsDigit(c):
+		start := l.pos
+		for l.pos < len(l.src) && (unicode.IsDigit(l.src[l.pos]) || l.src[l.pos] == '.') {
+			l.pos++
+		}
+		text := string(l.src[start:l.pos])
+		n, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return token{}, fmt.Errorf("expr: invalid number %q", text)
+		}
+		return token{kind: tNumber, text: text, num: n}, nil
+
+	case c == '"' || c == '\'':
+		quote := c
+		l.pos++
+		var sb strings.Builder
+		for l.pos < len(l.src) && l.src[l.pos] != quote {
+			sb.WriteRune(l.src[l.pos])
+			l.pos++
+		}
+		if l.pos >= len(l.src) {
+			return token{}, fmt.Errorf("expr: unterminated string literal")
+		}
+		l.pos++
+		return token{kind: tString, text: sb.String()}, nil
+
+	case unicode.IsLetter(c) || c == '_':
+		start := l.pos
+		for l.pos < len(l.src) && (unicode.IsLetter(l.src[l.pos]) || unicode.IsDigit(l.src[l.pos]) || l.src[l.pos] == '_') {
+			l.pos++
+		}
+		text := string(l.src[start:l.pos])
+		if keywords[text] {
+			return token{kind: tKeyword, text: text}, nil
+		}
+		return token{kind: tIdent, text: text}, nil
+
+	default:
+		two := ""
+		if l.pos+1 < len(l.src) {
+			two = string(l.src[l.pos : l.pos+2])
+		}
+		switch two {
+		case "==", "!=", "<=", ">=", "&&", "||":
+			l.pos += 2
+			return token{kind: tPunct, text: two}, nil
+		}
+
+		one := string(c)
+		switch one {
+		case "+", "-", "*", "/", "%", "(", ")", "[", "]", "{", "}", ".", ",", "<", ">", "?", ":", "!", "=":
+			l.pos++
+			return token{kind: tPunct, text: one}, nil
+		}
+
+		return token{}, fmt.Errorf("expr: unexpected character %q", one)
+	}
+}
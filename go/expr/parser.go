@@ -0,0 +1,451 @@
+package expr
+
+import "fmt"
+
+// parser is a recursive-descent parser producing an AST (see ast.go) from
+// the token stream, with the usual precedence climbing: or, and, not, in,
+// comparison, add/sub, mul/div/mod, unary, postfix (.field / [index]),
+// primary (literals, identifiers, name(args) calls, parenthesised
+// expressions, array and object literals).
+type parser struct {
+	toks []token
+	pos  int
+	// noIn suppresses the `in` membership operator while parsing a let
+	// binding's value expression, so its trailing `in` is left for
+	// parseLet to consume as the let/in keyword rather than being eaten
+	// as `value in something`.
+	noIn bool
+}
+
+func parse(src string) (node, error) {
+	toks, err := newLexer(src).tokens()
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{toks: toks}
+
+	n, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.cur().kind != tEOF {
+		return nil, fmt.Errorf("expr: unexpected trailing token %q", p.cur().text)
+	}
+	return n, nil
+}
+
+func (p *parser) cur() token  { return p.toks[p.pos] }
+func (p *parser) advance() token {
+	t := p.toks[p.pos]
+	if p.pos < len(p.toks)-1 {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isPunct(s string) bool {
+	return p.cur().kind == tPunct && p.cur().text == s
+}
+
+func (p *parser) isKeyword(s string) bool {
+	return p.cur().kind == tKeyword && p.cur().text == s
+}
+
+func (p *parser) expectPunct(s string) error {
+	if !p.isPunct(s) {
+		return fmt.Errorf("expr: expected %q, got %q", s, p.cur().text)
+	}
+	p.advance()
+	return nil
+}
+
+func (p *parser) parseExpr() (node, error) {
+	if p.isKeyword("let") {
+		return p.parseLet()
+	}
+	return p.parseTernary()
+}
+
+func (p *parser) parseLet() (node, error) {
+	p.advance() // 'let'
+	if p.cur().kind != tIdent {
+		return nil, fmt.Errorf("expr: expected identifier after let")
+	}
+	name := p.advance().text
+
+	if !p.isPunct("=") {
+		// '=' is tokenized as punct by the lexer via single-char fallback;
+		// ensure lexer emits it (added to lex.go punct set).
+		return nil, fmt.Errorf("expr: expected '=' in let binding")
+	}
+	p.advance()
+
+	prevNoIn := p.noIn
+	p.noIn = true
+	value, err := p.parseTernary()
+	p.noIn = prevNoIn
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.isKeyword("in") {
+		return nil, fmt.Errorf("expr: expected 'in' in let binding")
+	}
+	p.advance()
+
+	body, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+
+	return &letExpr{name: name, value: value, body: body}, nil
+}
+
+// parseTernary handles the Python-style postfix form: `then if cond else els`.
+func (p *parser) parseTernary() (node, error) {
+	then, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.isKeyword("if") {
+		p.advance()
+		cond, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.isKeyword("else") {
+			return nil, fmt.Errorf("expr: expected 'else' in ternary")
+		}
+		p.advance()
+		els, err := p.parseTernary()
+		if err != nil {
+			return nil, err
+		}
+		return &ternary{cond: cond, then: then, els: els}, nil
+	}
+
+	return then, nil
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("or") || p.isPunct("||") {
+		p.advance()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binary{op: "or", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("and") || p.isPunct("&&") {
+		p.advance()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binary{op: "and", left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.isKeyword("not") || p.isPunct("!") {
+		p.advance()
+		val, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unary{op: "not", val: val}, nil
+	}
+	return p.parseIn()
+}
+
+func (p *parser) parseIn() (node, error) {
+	left, err := p.parseCmp()
+	if err != nil {
+		return nil, err
+	}
+	if !p.noIn && p.isKeyword("in") {
+		p.advance()
+		right, err := p.parseCmp()
+		if err != nil {
+			return nil, err
+		}
+		return &inExpr{elem: left, list: right}, nil
+	}
+	return left, nil
+}
+
+var cmpOps = map[string]bool{"==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true}
+
+func (p *parser) parseCmp() (node, error) {
+	left, err := p.parseAdd()
+	if err != nil {
+		return nil, err
+	}
+	for p.cur().kind == tPunct && cmpOps[p.cur().text] {
+		op := p.advance().text
+		right, err := p.parseAdd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binary{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAdd() (node, error) {
+	left, err := p.parseMul()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("+") || p.isPunct("-") {
+		op := p.advance().text
+		right, err := p.parseMul()
+		if err != nil {
+			return nil, err
+		}
+		left = &binary{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseMul() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isPunct("*") || p.isPunct("/") || p.isPunct("%") {
+		op := p.advance().text
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binary{op: op, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if p.isPunct("-") {
+		p.advance()
+		val, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unary{op: "-", val: val}, nil
+	}
+	return p.parsePostfix()
+}
+
+func (p *parser) parsePostfix() (node, error) {
+	n, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch {
+		case p.isPunct("."):
+			p.advance()
+			if p.cur().kind != tIdent {
+				return nil, fmt.Errorf("expr: expected field name after '.'")
+			}
+			name := p.advance().text
+			n = &field{target: n, name: name}
+
+		case p.isPunct("["):
+			p.advance()
+			prevNoIn := p.noIn
+			p.noIn = false
+			idx, err := p.parseExpr()
+			p.noIn = prevNoIn
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expectPunct("]"); err != nil {
+				return nil, err
+			}
+			n = &index{target: n, idx: idx}
+
+		default:
+			return n, nil
+		}
+	}
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.cur()
+
+	switch {
+	case t.kind == tNumber:
+		p.advance()
+		return &numLit{val: t.num}, nil
+
+	case t.kind == tString:
+		p.advance()
+		return &strLit{val: t.text}, nil
+
+	case t.kind == tKeyword && t.text == "true":
+		p.advance()
+		return &boolLit{val: true}, nil
+
+	case t.kind == tKeyword && t.text == "false":
+		p.advance()
+		return &boolLit{val: false}, nil
+
+	case t.kind == tKeyword && t.text == "null":
+		p.advance()
+		return &nullLit{}, nil
+
+	case t.kind == tIdent:
+		name := p.advance().text
+		if p.isPunct("(") {
+			p.advance()
+			args, err := p.parseArgs()
+			if err != nil {
+				return nil, err
+			}
+			return &call{name: name, args: args}, nil
+		}
+		return &ident{name: name}, nil
+
+	case t.kind == tPunct && t.text == "(":
+		p.advance()
+		prevNoIn := p.noIn
+		p.noIn = false
+		n, err := p.parseExpr()
+		p.noIn = prevNoIn
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expectPunct(")"); err != nil {
+			return nil, err
+		}
+		return n, nil
+
+	case t.kind == tPunct && t.text == "[":
+		return p.parseArrayLit()
+
+	case t.kind == tPunct && t.text == "{":
+		return p.parseObjectLit()
+
+	default:
+		return nil, fmt.Errorf("expr: unexpected token %q", t.text)
+	}
+}
+
+// parseArrayLit parses `[expr, expr, ...]`.
+func (p *parser) parseArrayLit() (node, error) {
+	p.advance() // '['
+	prevNoIn := p.noIn
+	p.noIn = false
+	defer func() { p.noIn = prevNoIn }()
+
+	var items []node
+	if p.isPunct("]") {
+		p.advance()
+		return &arrLit{items: items}, nil
+	}
+	for {
+		item, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+		if p.isPunct(",") {
+			p.advance()
+			continue
+		}
+		break
+	}
+	if err := p.expectPunct("]"); err != nil {
+		return nil, err
+	}
+	return &arrLit{items: items}, nil
+}
+
+// parseObjectLit parses `{"key": expr, ident: expr, ...}`. A key is either
+// a string literal or a bare identifier, used as a literal key name.
+func (p *parser) parseObjectLit() (node, error) {
+	p.advance() // '{'
+	prevNoIn := p.noIn
+	p.noIn = false
+	defer func() { p.noIn = prevNoIn }()
+
+	var keys []string
+	var vals []node
+	if p.isPunct("}") {
+		p.advance()
+		return &objLit{keys: keys, vals: vals}, nil
+	}
+	for {
+		var key string
+		switch {
+		case p.cur().kind == tString:
+			key = p.advance().text
+		case p.cur().kind == tIdent:
+			key = p.advance().text
+		default:
+			return nil, fmt.Errorf("expr: expected object key, got %q", p.cur().text)
+		}
+		if err := p.expectPunct(":"); err != nil {
+			return nil, err
+		}
+		val, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+		vals = append(vals, val)
+		if p.isPunct(",") {
+			p.advance()
+			continue
+		}
+		break
+	}
+	if err := p.expectPunct("}"); err != nil {
+		return nil, err
+	}
+	return &objLit{keys: keys, vals: vals}, nil
+}
+
+func (p *parser) parseArgs() ([]node, error) {
+	prevNoIn := p.noIn
+	p.noIn = false
+	defer func() { p.noIn = prevNoIn }()
+
+	var args []node
+	if p.isPunct(")") {
+		p.advance()
+		return args, nil
+	}
+	for {
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		if p.isPunct(",") {
+			p.advance()
+			continue
+		}
+		break
+	}
+	if err := p.expectPunct(")"); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
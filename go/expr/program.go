@@ -0,0 +1,55 @@
+/* Copyright (c) 2025 Voxgig Ltd. MIT LICENSE. */
+
+// Package expr implements a small expression sublanguage embedded in the
+// same backtick injection syntax voxgigstruct.Transform already uses:
+// any string starting with "= " (e.g. `` `= a + b * 2` ``) is compiled
+// once to a bytecode Program and then evaluated directly against the
+// current Injection's current/store, instead of a plain path lookup.
+//
+// The grammar covers number/string/bool/null literals, identifiers
+// resolved via GetPath, `.field` and `[idx]` accessors, arithmetic,
+// comparison and logical operators, ternaries (`then if cond else els`),
+// `in`, string concatenation via `+`, a `let name = value in body` form,
+// and calls into a pluggable function table.
+package expr
+
+import "fmt"
+
+// Program is a compiled expression, ready for repeated evaluation without
+// re-parsing.
+type Program struct {
+	src string
+	ops []op
+}
+
+// Source returns the original expression source this Program was compiled
+// from.
+func (p *Program) Source() string {
+	return p.src
+}
+
+// Compile parses and compiles an expression source string into a Program.
+func Compile(src string) (*Program, error) {
+	ast, err := parse(src)
+	if err != nil {
+		return nil, fmt.Errorf("expr: %s: %w", src, err)
+	}
+
+	ops, err := compile(ast)
+	if err != nil {
+		return nil, fmt.Errorf("expr: %s: %w", src, err)
+	}
+
+	return &Program{src: src, ops: ops}, nil
+}
+
+// Expr is an alias of Compile, matching the public entry point named in
+// the feature request: voxgigstruct.Expr / expr.Expr.
+func Expr(src string) (*Program, error) {
+	return Compile(src)
+}
+
+// Eval runs the compiled Program against ctx.
+func (p *Program) Eval(ctx EvalContext) (any, error) {
+	return run(p.ops, ctx)
+}
@@ -0,0 +1,157 @@
+package expr
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// getProp safely reads a property of a map, or an element of a list/array,
+// by key/index. It mirrors voxgigstruct.GetProp's behaviour on the shapes
+// expressions actually see (map[string]any / []any), without importing
+// voxgigstruct (which imports expr for Transform/Inject support).
+func getProp(val any, key any) any {
+	if val == nil || key == nil {
+		return nil
+	}
+
+	if m, ok := val.(map[string]any); ok {
+		return m[toStringValue(key)]
+	}
+
+	if list, ok := val.([]any); ok {
+		i, ok := toIndex(key)
+		if !ok || i < 0 || i >= len(list) {
+			return nil
+		}
+		return list[i]
+	}
+
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		i, ok := toIndex(key)
+		if !ok || i < 0 || i >= rv.Len() {
+			return nil
+		}
+		return rv.Index(i).Interface()
+
+	case reflect.Map:
+		kv := reflect.ValueOf(toStringValue(key))
+		v := rv.MapIndex(kv)
+		if !v.IsValid() {
+			return nil
+		}
+		return v.Interface()
+	}
+
+	return nil
+}
+
+func toIndex(key any) (int, bool) {
+	switch k := key.(type) {
+	case float64:
+		return int(k), true
+	case int:
+		return k, true
+	case string:
+		i, err := strconv.Atoi(k)
+		return i, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func truthy(v any) bool {
+	switch x := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return x
+	case string:
+		return x != ""
+	case float64:
+		return x != 0
+	case []any:
+		return len(x) > 0
+	case map[string]any:
+		return len(x) > 0
+	default:
+		return true
+	}
+}
+
+func toNumber(v any) (float64, error) {
+	switch x := v.(type) {
+	case float64:
+		return x, nil
+	case int:
+		return float64(x), nil
+	case bool:
+		if x {
+			return 1, nil
+		}
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("expr: expected a number, got %T", v)
+	}
+}
+
+func toStringValue(v any) string {
+	switch x := v.(type) {
+	case string:
+		return x
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(x)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func equal(a, b any) bool {
+	an, aerr := toNumber(a)
+	bn, berr := toNumber(b)
+	if aerr == nil && berr == nil {
+		return an == bn
+	}
+	return a == b
+}
+
+func contains(list, elem any) bool {
+	switch l := list.(type) {
+	case []any:
+		for _, v := range l {
+			if equal(v, elem) {
+				return true
+			}
+		}
+		return false
+
+	case map[string]any:
+		_, has := l[toStringValue(elem)]
+		return has
+
+	case string:
+		sub := toStringValue(elem)
+		return sub == "" || containsSubstring(l, sub)
+
+	default:
+		return false
+	}
+}
+
+func containsSubstring(s, sub string) bool {
+	if len(sub) > len(s) {
+		return false
+	}
+	for i := 0; i+len(sub) <= len(s); i++ {
+		if s[i:i+len(sub)] == sub {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,255 @@
+package expr
+
+import (
+	"fmt"
+)
+
+// Func is a function registered in the expression function table, called
+// via e.g. `upper(name)`.
+type Func func(args ...any) (any, error)
+
+// EvalContext supplies the data an expression resolves identifiers and
+// calls against: Current/Store mirror voxgigstruct.GetPathState's
+// current/store, Funcs is the pluggable function table (wired through the
+// same `extra` map callers already use to register `$UPPER`-style
+// injectors), and Path is the Injection path, attached to any error so
+// validation-style callers can collect it via the existing error
+// mechanism.
+type EvalContext struct {
+	Current any
+	Store   any
+	Funcs   map[string]Func
+	Path    []string
+}
+
+// EvalError wraps an evaluation failure with the Injection path active
+// when it occurred.
+type EvalError struct {
+	Path []string
+	Err  error
+}
+
+func (e *EvalError) Error() string {
+	if len(e.Path) == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s at %v", e.Err.Error(), e.Path)
+}
+
+func (e *EvalError) Unwrap() error { return e.Err }
+
+type vm struct {
+	ops    []op
+	stack  []any
+	locals map[string]any
+	ctx    EvalContext
+}
+
+func (m *vm) push(v any) { m.stack = append(m.stack, v) }
+
+func (m *vm) pop() any {
+	n := len(m.stack)
+	v := m.stack[n-1]
+	m.stack = m.stack[:n-1]
+	return v
+}
+
+func run(ops []op, ctx EvalContext) (any, error) {
+	m := &vm{ops: ops, locals: map[string]any{}, ctx: ctx}
+
+	for pc := 0; pc < len(m.ops); pc++ {
+		o := m.ops[pc]
+
+		switch o.Code {
+		case OpLoadConst:
+			m.push(o.Val)
+
+		case OpLoadPath:
+			name := o.Path[0]
+			if local, ok := m.locals[name]; ok {
+				m.push(local)
+				break
+			}
+			m.push(resolveIdent(name, ctx))
+
+		case OpStoreLocal:
+			m.locals[o.Name] = m.pop()
+
+		case OpField:
+			target := m.pop()
+			m.push(getProp(target, o.Name))
+
+		case OpIndex:
+			idx := m.pop()
+			target := m.pop()
+			m.push(getProp(target, idx))
+
+		case OpCall:
+			args := make([]any, o.Argc)
+			for i := o.Argc - 1; i >= 0; i-- {
+				args[i] = m.pop()
+			}
+			fn, ok := ctx.Funcs[o.Name]
+			if !ok {
+				return nil, &EvalError{Path: ctx.Path, Err: fmt.Errorf("expr: unknown function %q", o.Name)}
+			}
+			res, err := fn(args...)
+			if err != nil {
+				return nil, &EvalError{Path: ctx.Path, Err: err}
+			}
+			m.push(res)
+
+		case OpAdd:
+			if err := m.arith(o.Code); err != nil {
+				return nil, err
+			}
+		case OpSub, OpMul, OpDiv, OpMod:
+			if err := m.arith(o.Code); err != nil {
+				return nil, err
+			}
+
+		case OpNeg:
+			v, err := toNumber(m.pop())
+			if err != nil {
+				return nil, &EvalError{Path: ctx.Path, Err: err}
+			}
+			m.push(-v)
+
+		case OpNot:
+			m.push(!truthy(m.pop()))
+
+		case OpEq:
+			b, a := m.pop(), m.pop()
+			m.push(equal(a, b))
+
+		case OpNeq:
+			b, a := m.pop(), m.pop()
+			m.push(!equal(a, b))
+
+		case OpLt, OpLte, OpGt, OpGte:
+			if err := m.compare(o.Code); err != nil {
+				return nil, err
+			}
+
+		case OpIn:
+			list, elem := m.pop(), m.pop()
+			m.push(contains(list, elem))
+
+		case OpJump:
+			pc = o.Addr - 1
+
+		case OpJumpIfFalse:
+			if !truthy(m.pop()) {
+				pc = o.Addr - 1
+			}
+
+		case OpPop:
+			m.pop()
+
+		case OpMakeArray:
+			items := make([]any, o.Argc)
+			for i := o.Argc - 1; i >= 0; i-- {
+				items[i] = m.pop()
+			}
+			m.push(items)
+
+		case OpMakeObject:
+			obj := make(map[string]any, o.Argc)
+			vals := make([]any, o.Argc)
+			for i := o.Argc - 1; i >= 0; i-- {
+				vals[i] = m.pop()
+			}
+			for i, key := range o.Keys {
+				obj[key] = vals[i]
+			}
+			m.push(obj)
+
+		default:
+			return nil, &EvalError{Path: ctx.Path, Err: fmt.Errorf("expr: unknown opcode %d", o.Code)}
+		}
+	}
+
+	if len(m.stack) == 0 {
+		return nil, nil
+	}
+	return m.stack[len(m.stack)-1], nil
+}
+
+// resolveIdent resolves a bare identifier, trying the current (local) node
+// first, then falling back to the top-level store - the same local-first
+// resolution order voxgigstruct.GetPathState uses for relative paths.
+func resolveIdent(name string, ctx EvalContext) any {
+	if v := getProp(ctx.Current, name); v != nil {
+		return v
+	}
+	return getProp(ctx.Store, name)
+}
+
+func (m *vm) arith(code opCode) error {
+	b, a := m.pop(), m.pop()
+
+	// String concatenation via '+' when either side is a string.
+	if code == OpAdd {
+		if as, ok := a.(string); ok {
+			m.push(as + toStringValue(b))
+			return nil
+		}
+		if bs, ok := b.(string); ok {
+			m.push(toStringValue(a) + bs)
+			return nil
+		}
+	}
+
+	af, err := toNumber(a)
+	if err != nil {
+		return &EvalError{Path: m.ctx.Path, Err: err}
+	}
+	bf, err := toNumber(b)
+	if err != nil {
+		return &EvalError{Path: m.ctx.Path, Err: err}
+	}
+
+	switch code {
+	case OpAdd:
+		m.push(af + bf)
+	case OpSub:
+		m.push(af - bf)
+	case OpMul:
+		m.push(af * bf)
+	case OpDiv:
+		if bf == 0 {
+			return &EvalError{Path: m.ctx.Path, Err: fmt.Errorf("expr: division by zero")}
+		}
+		m.push(af / bf)
+	case OpMod:
+		if bf == 0 {
+			return &EvalError{Path: m.ctx.Path, Err: fmt.Errorf("expr: modulo by zero")}
+		}
+		m.push(float64(int64(af) % int64(bf)))
+	}
+	return nil
+}
+
+func (m *vm) compare(code opCode) error {
+	b, a := m.pop(), m.pop()
+	af, err := toNumber(a)
+	if err != nil {
+		return &EvalError{Path: m.ctx.Path, Err: err}
+	}
+	bf, err := toNumber(b)
+	if err != nil {
+		return &EvalError{Path: m.ctx.Path, Err: err}
+	}
+
+	switch code {
+	case OpLt:
+		m.push(af < bf)
+	case OpLte:
+		m.push(af <= bf)
+	case OpGt:
+		m.push(af > bf)
+	case OpGte:
+		m.push(af >= bf)
+	}
+	return nil
+}
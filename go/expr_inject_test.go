@@ -0,0 +1,88 @@
+package voxgigstruct_test
+
+import (
+	"strings"
+	"testing"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+// TestInjectExpr covers the `` `= ...` `` expression-injection syntax added
+// on top of the existing `` `a.b.c` `` path injections.
+func TestInjectExpr(t *testing.T) {
+	store := map[string]any{
+		"a": float64(2),
+		"b": float64(3),
+	}
+
+	spec := map[string]any{
+		"x": "`= a + b * 2`",
+	}
+
+	out := voxgigstruct.Inject(spec, store).(map[string]any)
+
+	if out["x"] != float64(8) {
+		t.Fatalf("expected 8, got %v", out["x"])
+	}
+}
+
+func TestInjectExprInvalidReturnsNil(t *testing.T) {
+	store := map[string]any{}
+
+	spec := map[string]any{
+		"x": "`= 1 +`",
+	}
+
+	out := voxgigstruct.Inject(spec, store).(map[string]any)
+	if out["x"] != nil {
+		t.Fatalf("expected nil for invalid expression, got %v", out["x"])
+	}
+}
+
+// TestInjectExprPrefixForm covers the explicit `=expr:` delimiter alongside
+// the `= ...` shorthand, and that it can call a builtin function.
+func TestInjectExprPrefixForm(t *testing.T) {
+	store := map[string]any{
+		"price":    float64(100),
+		"discount": float64(0.25),
+	}
+
+	spec := map[string]any{
+		"total": "`=expr:price * (1 - discount)`",
+	}
+
+	out := voxgigstruct.Inject(spec, store).(map[string]any)
+	if out["total"] != float64(75) {
+		t.Fatalf("expected 75, got %v", out["total"])
+	}
+}
+
+func TestInjectExprRegisteredFunc(t *testing.T) {
+	voxgigstruct.RegisterExprFunc("shout", func(args ...any) (any, error) {
+		s, _ := args[0].(string)
+		return strings.ToUpper(s) + "!", nil
+	})
+
+	store := map[string]any{"name": "hi"}
+	spec := map[string]any{"x": "`=expr:shout(name)`"}
+
+	out := voxgigstruct.Inject(spec, store).(map[string]any)
+	if out["x"] != "HI!" {
+		t.Fatalf("expected HI!, got %v", out["x"])
+	}
+}
+
+// TestInjectExprErrorPropagatesToErrs confirms a failing expression reaches
+// the caller-supplied $ERRS collector, the same path addErr uses for
+// Validate/ValidateCollect.
+func TestInjectExprErrorPropagatesToErrs(t *testing.T) {
+	errs := voxgigstruct.ListRefCreate[any]()
+	store := map[string]any{"$ERRS": errs}
+
+	spec := map[string]any{"x": "`=expr:1 / 0`"}
+	voxgigstruct.Inject(spec, store)
+
+	if len(errs.List) == 0 {
+		t.Fatal("expected the division-by-zero expression error to be collected")
+	}
+}
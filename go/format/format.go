@@ -0,0 +1,264 @@
+/* Copyright (c) 2025 Voxgig Ltd. MIT LICENSE. */
+
+/* Package format is a small format-directed printer: callers register
+ * named template rules keyed by Go type, by voxgigstruct.Typify shape
+ * tag (e.g. "string", "array"), or by "struct:TypeName" for a named
+ * struct, and Render walks a value applying whichever rule matches.
+ *
+ * Templates are plain strings with a handful of placeholders:
+ *
+ *	%v          the value, stringified
+ *	%k          the current key (map key, struct field name, list index)
+ *	%p          the full '.'-joined path to the value
+ *	%t          the detected type, via voxgigstruct.Typify
+ *	%a          a memory address, for maps/slices/pointers (best effort,
+ *	            as produced by reflect; "0x???" when not addressable)
+ *	{{child}}   recurses into the value's own children (map entries,
+ *	            list elements, or exported struct fields), joined by the
+ *	            separator set with {{join sep}} (default ", ")
+ *	{{join X}}  sets that separator; consumed from the template, emits
+ *	            nothing itself
+ *
+ * Two ready-made rule sets are provided: DebugRules, which reproduces
+ * the package's former ad-hoc fdt/fdti debug printer, and ErrorRules,
+ * which renders a *voxgigstruct.ListRef[*voxgigstruct.ValidationError]
+ * as a tree grouped by path prefix. NewErrorFormatter adapts a RuleSet
+ * into a voxgigstruct.ErrorFormatter, for use as extra["$FORMAT"] in a
+ * call to voxgigstruct.ValidateCollect/ValidateCollectStructured.
+ */
+package format
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+// RuleSet maps a lookup key to a template string. A value is rendered by
+// trying, in order: "struct:<Name>" for a named struct, the
+// voxgigstruct.Typify shape tag, the bare reflect.Type string, then "*"
+// as a catch-all default. "nil" is tried first of all for a nil value.
+type RuleSet map[string]string
+
+// Render walks data, applying rules, and returns the rendered string. A
+// value with no matching rule (including "*") falls back to fmt.Sprint.
+func Render(data any, rules RuleSet) string {
+	return renderValue(data, rules, "", nil)
+}
+
+func renderValue(data any, rules RuleSet, key string, path []string) string {
+	tmpl, ok := ruleFor(data, rules)
+	if !ok {
+		return fmt.Sprint(data)
+	}
+
+	sep := ", "
+	tmpl, sep = extractJoin(tmpl, sep)
+
+	out := tmpl
+	out = strings.ReplaceAll(out, "%k", key)
+	out = strings.ReplaceAll(out, "%p", strings.Join(path, "."))
+	out = strings.ReplaceAll(out, "%t", voxgigstruct.Typify(data))
+	out = strings.ReplaceAll(out, "%a", memoryAddr(data))
+	out = strings.ReplaceAll(out, "%v", fmt.Sprint(data))
+
+	if strings.Contains(out, "{{child}}") {
+		out = strings.ReplaceAll(out, "{{child}}", renderChildren(data, rules, path, sep))
+	}
+
+	return out
+}
+
+// extractJoin pulls a "{{join X}}" directive out of tmpl, returning the
+// template with it removed and X as the separator, or the unchanged
+// template and fallback when no directive is present.
+func extractJoin(tmpl string, fallback string) (string, string) {
+	start := strings.Index(tmpl, "{{join ")
+	if start < 0 {
+		return tmpl, fallback
+	}
+	rest := tmpl[start+len("{{join "):]
+	end := strings.Index(rest, "}}")
+	if end < 0 {
+		return tmpl, fallback
+	}
+	sep := rest[:end]
+	return tmpl[:start] + rest[end+2:], sep
+}
+
+func renderChildren(data any, rules RuleSet, path []string, sep string) string {
+	childPath := func(k string) []string {
+		out := make([]string, len(path)+1)
+		copy(out, path)
+		out[len(path)] = k
+		return out
+	}
+
+	switch v := data.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		parts := make([]string, 0, len(keys))
+		for _, k := range keys {
+			parts = append(parts, renderValue(v[k], rules, k, childPath(k)))
+		}
+		return strings.Join(parts, sep)
+
+	case []any:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			k := strconv.Itoa(i)
+			parts[i] = renderValue(item, rules, k, childPath(k))
+		}
+		return strings.Join(parts, sep)
+
+	default:
+		rv := reflect.ValueOf(data)
+		for rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return ""
+			}
+			rv = rv.Elem()
+		}
+		if rv.Kind() != reflect.Struct {
+			return ""
+		}
+
+		t := rv.Type()
+		parts := make([]string, 0, rv.NumField())
+		for i := 0; i < rv.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			parts = append(parts, renderValue(rv.Field(i).Interface(), rules, f.Name, childPath(f.Name)))
+		}
+		return strings.Join(parts, sep)
+	}
+}
+
+func ruleFor(data any, rules RuleSet) (string, bool) {
+	if data == nil {
+		if tmpl, ok := rules["nil"]; ok {
+			return tmpl, true
+		}
+		return "", false
+	}
+
+	rv := reflect.ValueOf(data)
+	base := rv
+	for base.Kind() == reflect.Ptr && !base.IsNil() {
+		base = base.Elem()
+	}
+	if base.IsValid() && base.Kind() == reflect.Struct {
+		if tmpl, ok := rules["struct:"+base.Type().Name()]; ok {
+			return tmpl, true
+		}
+	}
+
+	if tmpl, ok := rules[voxgigstruct.Typify(data)]; ok {
+		return tmpl, true
+	}
+	if tmpl, ok := rules[reflect.TypeOf(data).String()]; ok {
+		return tmpl, true
+	}
+	if tmpl, ok := rules["*"]; ok {
+		return tmpl, true
+	}
+	return "", false
+}
+
+// memoryAddr renders data's underlying address the same way the old fdti
+// debug printer did: the map/slice header pointer, a pointer's own
+// target address, or "0x???" for anything else (matching fdti's
+// fallback for unaddressable values).
+func memoryAddr(data any) string {
+	if data == nil {
+		return "0x???"
+	}
+
+	rv := reflect.ValueOf(data)
+	switch {
+	case rv.Kind() == reflect.Ptr:
+		return fmt.Sprintf("0x%x", rv.Pointer())
+	case rv.Kind() == reflect.Map || rv.Kind() == reflect.Slice:
+		return fmt.Sprintf("0x%x", rv.Pointer())
+	case rv.CanAddr():
+		return fmt.Sprintf("0x%x", rv.Addr().Pointer())
+	default:
+		return "0x???"
+	}
+}
+
+// DebugRules reproduces the shape of voxgigstruct's former ad-hoc fdt/fdti
+// debug printer: braces/brackets for maps and lists with a memory address,
+// and a "key: value (type) @address" line for everything else.
+var DebugRules = RuleSet{
+	"object": "{ @%a {{child}} }",
+	"array":  "[ @%a {{child}} ]",
+	"*":      "%k: %v (%t) @%a",
+}
+
+// NewErrorFormatter adapts rules into a voxgigstruct.ErrorFormatter,
+// suitable for extra["$FORMAT"] in a call to
+// voxgigstruct.ValidateCollect/ValidateCollectStructured.
+func NewErrorFormatter(rules RuleSet) voxgigstruct.ErrorFormatter {
+	return func(errs *voxgigstruct.ListRef[*voxgigstruct.ValidationError]) string {
+		return RenderErrors(errs, rules)
+	}
+}
+
+// RenderErrors renders errs as a tree grouped by path prefix (the first
+// path segment of each error, or "(root)" for an empty path). Each
+// *ValidationError is rendered through rules["error"] (default "%p: %v"),
+// with %p replaced by the error's Pointer and %v by its Message; no other
+// rule in rules is consulted.
+func RenderErrors(errs *voxgigstruct.ListRef[*voxgigstruct.ValidationError], rules RuleSet) string {
+	if errs == nil || len(errs.List) == 0 {
+		return "No validation errors"
+	}
+
+	groups := map[string][]*voxgigstruct.ValidationError{}
+	var groupOrder []string
+	for _, e := range errs.List {
+		prefix := "(root)"
+		if len(e.Path) > 0 {
+			prefix = fmt.Sprint(e.Path[0])
+		}
+		if _, seen := groups[prefix]; !seen {
+			groupOrder = append(groupOrder, prefix)
+		}
+		groups[prefix] = append(groups[prefix], e)
+	}
+	sort.Strings(groupOrder)
+
+	tmpl, ok := rules["error"]
+	if !ok {
+		tmpl = "%p: %v"
+	}
+
+	var b strings.Builder
+	for _, prefix := range groupOrder {
+		fmt.Fprintf(&b, "%s:\n", prefix)
+		for _, e := range groups[prefix] {
+			line := strings.ReplaceAll(tmpl, "%p", e.Pointer)
+			line = strings.ReplaceAll(line, "%v", e.Message)
+			fmt.Fprintf(&b, "  - %s\n", line)
+		}
+	}
+	return b.String()
+}
+
+// ErrorRules renders each ValidationError as "<pointer>: <message>" under
+// its path-prefix group heading (see RenderErrors).
+var ErrorRules = RuleSet{
+	"error": "%p: %v",
+}
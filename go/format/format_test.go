@@ -0,0 +1,90 @@
+package format
+
+import (
+	"strings"
+	"testing"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+func TestRenderAppliesTypeTagRule(t *testing.T) {
+	rules := RuleSet{
+		"string": "str(%v)",
+		"*":      "%v",
+	}
+	got := Render("hi", rules)
+	if got != "str(hi)" {
+		t.Fatalf("expected \"str(hi)\", got %q", got)
+	}
+}
+
+func TestRenderRecursesWithChildAndJoin(t *testing.T) {
+	rules := RuleSet{
+		"object": "{{join ; }}{{child}}",
+		"*":      "%k=%v",
+	}
+	got := Render(map[string]any{"a": 1, "b": 2}, rules)
+	if got != "a=1; b=2" {
+		t.Fatalf("expected \"a=1; b=2\", got %q", got)
+	}
+}
+
+func TestRenderFallsBackToFmtSprintWithoutMatchingRule(t *testing.T) {
+	got := Render(42, RuleSet{})
+	if got != "42" {
+		t.Fatalf("expected \"42\", got %q", got)
+	}
+}
+
+func TestRenderPathPlaceholderReflectsNesting(t *testing.T) {
+	rules := RuleSet{
+		"object": "{{child}}",
+		"*":      "%p=%v ",
+	}
+	got := Render(map[string]any{"a": map[string]any{"b": 1}}, rules)
+	if !strings.Contains(got, "a.b=1") {
+		t.Fatalf("expected the nested path \"a.b\" to appear, got %q", got)
+	}
+}
+
+func TestNewErrorFormatterGroupsByPathPrefix(t *testing.T) {
+	errs := voxgigstruct.ListRefCreate[*voxgigstruct.ValidationError]()
+	errs.Append(&voxgigstruct.ValidationError{
+		Path: []any{"user", "name"}, Pointer: "/user/name", Message: "bad name",
+	})
+	errs.Append(&voxgigstruct.ValidationError{
+		Path: []any{"user", "age"}, Pointer: "/user/age", Message: "bad age",
+	})
+
+	formatter := NewErrorFormatter(ErrorRules)
+	out := formatter(errs)
+
+	if !strings.Contains(out, "user:") {
+		t.Fatalf("expected a \"user:\" group heading, got %q", out)
+	}
+	if !strings.Contains(out, "/user/name: bad name") {
+		t.Fatalf("expected the name error rendered under its group, got %q", out)
+	}
+	if !strings.Contains(out, "/user/age: bad age") {
+		t.Fatalf("expected the age error rendered under its group, got %q", out)
+	}
+}
+
+func TestValidateCollectUsesErrorFormatterFromExtra(t *testing.T) {
+	extra := map[string]any{
+		"$FORMAT": NewErrorFormatter(ErrorRules),
+	}
+
+	_, err := voxgigstruct.ValidateCollect(
+		map[string]any{"a": "A"},
+		map[string]any{"a": "`$NUMBER`"},
+		extra,
+		nil,
+	)
+	if err == nil {
+		t.Fatalf("expected a validation error")
+	}
+	if !strings.Contains(err.Error(), "/a:") {
+		t.Fatalf("expected the formatter's rendering in the error, got %v", err)
+	}
+}
@@ -0,0 +1,35 @@
+package voxgigstruct
+
+// TransformGuarded is TransformModify with injection recursion
+// safeguards attached: maxDepth caps how many levels InjectDescend will
+// recurse before giving up with a "Maximum injection depth exceeded"
+// error (0 means DefaultMaxDepth), and detectCycles additionally tracks
+// nodes currently on the descent stack so a spec or data structure that
+// loops back on itself (e.g. a self-referential map, or a $MERGE source
+// that resolves to one of its own ancestors) reports a clear
+// "Cycle detected" error instead of recursing until the stack
+// overflows.
+//
+// This is sugar over TransformModify's own extension point: MaxDepth
+// and DetectCycles travel through extra under the $MAXDEPTH/
+// $DETECTCYCLES keys, exactly like TransformTainted's Labels/Policy/
+// Guards.
+func TransformGuarded(
+	data any,
+	spec any,
+	extra any,
+	modify Modify,
+	maxDepth int,
+	detectCycles bool,
+) any {
+	guarded := map[string]any{}
+	if extra != nil {
+		for _, kv := range Items(extra) {
+			guarded[StrKey(kv[0])] = kv[1]
+		}
+	}
+	guarded[S_DMAXDEPTH] = maxDepth
+	guarded[S_DDETECTCYCLES] = detectCycles
+
+	return TransformModify(data, spec, guarded, modify)
+}
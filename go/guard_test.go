@@ -0,0 +1,109 @@
+package voxgigstruct_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+func hasErrContaining(errs *voxgigstruct.ListRef[any], substr string) bool {
+	for _, e := range errs.List {
+		if strings.Contains(errMessage(e), substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// errMessage extracts the human-readable message from an Injection.Errs
+// entry, which addErr stores as *voxgigstruct.ValidationError (or, for an
+// ad-hoc non-string/non-ValidationError value, whatever fmt.Sprint gives).
+func errMessage(e any) string {
+	switch v := e.(type) {
+	case *voxgigstruct.ValidationError:
+		return v.Message
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func TestInjectMaxDepthExceededReportsError(t *testing.T) {
+	nested := map[string]any{}
+	cursor := nested
+	for i := 0; i < 10; i++ {
+		child := map[string]any{}
+		cursor["child"] = child
+		cursor = child
+	}
+	cursor["leaf"] = "value"
+
+	errs := voxgigstruct.ListRefCreate[any]()
+	store := map[string]any{
+		"$ERRS":     errs,
+		"$MAXDEPTH": 3,
+	}
+
+	voxgigstruct.Inject(nested, store)
+
+	if !hasErrContaining(errs, "Maximum injection depth exceeded") {
+		t.Fatalf("expected a maximum-depth error for a 10-level nested structure capped at depth 3, got %+v", errs.List)
+	}
+}
+
+func TestInjectDetectCyclesSelfReferentialMap(t *testing.T) {
+	loop := map[string]any{"name": "node"}
+	loop["self"] = loop
+
+	errs := voxgigstruct.ListRefCreate[any]()
+	store := map[string]any{
+		"$ERRS":         errs,
+		"$DETECTCYCLES": true,
+	}
+
+	voxgigstruct.Inject(loop, store)
+
+	if !hasErrContaining(errs, "Cycle detected") {
+		t.Fatalf("expected a cycle-detected error for a self-referential map, got %+v", errs.List)
+	}
+}
+
+func TestInjectDetectCyclesAllowsSharedNonCyclicSubtree(t *testing.T) {
+	shared := map[string]any{"value": float64(1)}
+	spec := map[string]any{
+		"a": shared,
+		"b": shared,
+	}
+
+	errs := voxgigstruct.ListRefCreate[any]()
+	store := map[string]any{
+		"$ERRS":         errs,
+		"$DETECTCYCLES": true,
+	}
+
+	voxgigstruct.Inject(spec, store)
+
+	if len(errs.List) != 0 {
+		t.Fatalf("expected two sibling references to the same shared subtree not to be flagged as a cycle, got %+v", errs.List)
+	}
+}
+
+func TestTransformGuardedMaxDepthAppliesToTransform(t *testing.T) {
+	nested := map[string]any{}
+	cursor := nested
+	for i := 0; i < 10; i++ {
+		child := map[string]any{}
+		cursor["child"] = child
+		cursor = child
+	}
+
+	errs := voxgigstruct.ListRefCreate[any]()
+	voxgigstruct.TransformGuarded(map[string]any{}, nested, map[string]any{"$ERRS": errs}, nil, 3, false)
+
+	if !hasErrContaining(errs, "Maximum injection depth exceeded") {
+		t.Fatalf("expected TransformGuarded's MaxDepth to cap recursion, got %+v", errs.List)
+	}
+}
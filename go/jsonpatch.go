@@ -0,0 +1,344 @@
+package voxgigstruct
+
+import (
+	"strconv"
+	"strings"
+)
+
+// PatchOp is a single operation from an RFC 6902 JSON Patch document.
+// Value is used by add/replace/test, From by move/copy.
+type PatchOp struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value,omitempty"`
+	From  string `json:"from,omitempty"`
+}
+
+// Diff computes the minimal RFC 6902 patch that turns a into b, so
+// structural changes produced by Merge/Inject/Transform can be serialized
+// and replayed elsewhere via ApplyPatch. Scalars are replaced wholesale,
+// map keys are added/removed individually, and lists are aligned with a
+// longest-common-subsequence so that elements which only shift index
+// because of an insertion or removal elsewhere in the list don't show up
+// as spurious remove/add pairs. No move or copy ops are emitted: an element
+// relocated past others is reported as a remove and an add.
+func Diff(a, b any) []PatchOp {
+	var ops []PatchOp
+	diffPatchWalk(a, b, nil, &ops)
+	return ops
+}
+
+func diffPatchWalk(a, b any, path []string, ops *[]PatchOp) {
+	aNode := IsNode(a)
+	bNode := IsNode(b)
+
+	if !aNode && !bNode {
+		if !deepEqualValue(a, b) {
+			*ops = append(*ops, PatchOp{Op: "replace", Path: pointerPath(path), Value: b})
+		}
+		return
+	}
+
+	if !aNode || !bNode || IsMap(a) != IsMap(b) {
+		*ops = append(*ops, PatchOp{Op: "replace", Path: pointerPath(path), Value: b})
+		return
+	}
+
+	if IsList(a) {
+		diffPatchList(_listify(a), _listify(b), path, ops)
+		return
+	}
+
+	bVals := map[string]any{}
+	bPresent := map[string]bool{}
+	for _, kv := range Items(b) {
+		k := StrKey(kv[0])
+		bVals[k] = kv[1]
+		bPresent[k] = true
+	}
+
+	seen := map[string]bool{}
+	for _, kv := range Items(a) {
+		k := StrKey(kv[0])
+		seen[k] = true
+
+		if !bPresent[k] {
+			*ops = append(*ops, PatchOp{Op: "remove", Path: pointerPath(append(path, k))})
+			continue
+		}
+
+		diffPatchWalk(kv[1], bVals[k], append(path, k), ops)
+	}
+
+	for _, kv := range Items(b) {
+		k := StrKey(kv[0])
+		if seen[k] {
+			continue
+		}
+		*ops = append(*ops, PatchOp{Op: "add", Path: pointerPath(append(path, k)), Value: kv[1]})
+	}
+}
+
+// diffPatchList aligns a and b with a classic O(n*m) LCS so that only
+// elements outside the longest common subsequence are reported as
+// remove/add, rather than replacing every element from the first change
+// onward the way a naive index-by-index compare would.
+func diffPatchList(a, b []any, path []string, ops *[]PatchOp) {
+	n, m := len(a), len(b)
+
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if deepEqualValue(a[i], b[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	const (
+		stepKeep = iota
+		stepRemove
+		stepAdd
+	)
+	type step struct {
+		kind int
+		val  any
+	}
+
+	var steps []step
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case deepEqualValue(a[i], b[j]):
+			steps = append(steps, step{kind: stepKeep})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			steps = append(steps, step{kind: stepRemove})
+			i++
+		default:
+			steps = append(steps, step{kind: stepAdd, val: b[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		steps = append(steps, step{kind: stepRemove})
+	}
+	for ; j < m; j++ {
+		steps = append(steps, step{kind: stepAdd, val: b[j]})
+	}
+
+	// Ops apply in order against the array as it stands at that point, so
+	// a remove leaves idx where it is (the next element slides into it)
+	// while a keep or add moves past the position it just settled.
+	idx := 0
+	for _, s := range steps {
+		switch s.kind {
+		case stepKeep:
+			idx++
+		case stepRemove:
+			*ops = append(*ops, PatchOp{Op: "remove", Path: pointerPath(append(path, strconv.Itoa(idx)))})
+		case stepAdd:
+			*ops = append(*ops, PatchOp{Op: "add", Path: pointerPath(append(path, strconv.Itoa(idx))), Value: s.val})
+			idx++
+		}
+	}
+}
+
+func pointerPath(path []string) string {
+	if len(path) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, p := range path {
+		b.WriteByte('/')
+		b.WriteString(escapePointerToken(p))
+	}
+	return b.String()
+}
+
+// ApplyPatch applies an RFC 6902 JSON Patch document to root and returns
+// the result. Operations run against a clone of root, so the whole patch
+// applies atomically: if any operation fails - most usefully a "test" whose
+// value doesn't match - root is returned unchanged rather than a
+// partially-patched tree.
+func ApplyPatch(root any, ops []PatchOp) any {
+	working := Clone(root)
+
+	for _, op := range ops {
+		ok := applyPatchOp(&working, op)
+		if !ok {
+			return root
+		}
+	}
+
+	return working
+}
+
+func applyPatchOp(working *any, op PatchOp) bool {
+	switch op.Op {
+	case "add":
+		return patchAdd(working, op.Path, op.Value)
+
+	case "remove":
+		return patchRemove(working, op.Path)
+
+	case "replace":
+		if op.Path == "" {
+			*working = op.Value
+			return true
+		}
+		return patchRemove(working, op.Path) && patchAdd(working, op.Path, op.Value)
+
+	case "move":
+		val := GetPathJSONPointer(op.From, *working)
+		return patchRemove(working, op.From) && patchAdd(working, op.Path, val)
+
+	case "copy":
+		val := GetPathJSONPointer(op.From, *working)
+		return patchAdd(working, op.Path, val)
+
+	case "test":
+		return deepEqualValue(GetPathJSONPointer(op.Path, *working), op.Value)
+
+	default:
+		return false
+	}
+}
+
+func patchAdd(root *any, path string, value any) bool {
+	tokens := jsonPointerTokens(path)
+	if len(tokens) == 0 {
+		*root = value
+		return true
+	}
+
+	newRoot, ok := applyAtPointer(*root, tokens, func(parent any, last string) (any, bool) {
+		if IsList(parent) {
+			list := _listify(parent)
+			if last == "-" {
+				return append(list, value), true
+			}
+			ki, err := strconv.Atoi(last)
+			if err != nil || ki < 0 || ki > len(list) {
+				return parent, false
+			}
+			out := make([]any, 0, len(list)+1)
+			out = append(out, list[:ki]...)
+			out = append(out, value)
+			out = append(out, list[ki:]...)
+			return out, true
+		}
+		if IsMap(parent) {
+			return SetProp(parent, last, value), true
+		}
+		return parent, false
+	})
+	if !ok {
+		return false
+	}
+
+	*root = newRoot
+	return true
+}
+
+func patchRemove(root *any, path string) bool {
+	tokens := jsonPointerTokens(path)
+	if len(tokens) == 0 {
+		return false
+	}
+
+	newRoot, ok := applyAtPointer(*root, tokens, func(parent any, last string) (any, bool) {
+		if IsList(parent) {
+			list := _listify(parent)
+			ki, err := strconv.Atoi(last)
+			if err != nil || ki < 0 || ki >= len(list) {
+				return parent, false
+			}
+			out := make([]any, 0, len(list)-1)
+			out = append(out, list[:ki]...)
+			out = append(out, list[ki+1:]...)
+			return out, true
+		}
+		if IsMap(parent) {
+			m, ok := parent.(map[string]any)
+			if !ok {
+				return parent, false
+			}
+			if _, has := m[last]; !has {
+				return parent, false
+			}
+			delete(m, last)
+			return parent, true
+		}
+		return parent, false
+	})
+	if !ok {
+		return false
+	}
+
+	*root = newRoot
+	return true
+}
+
+// applyAtPointer descends container via tokens down to the parent of the
+// final segment, applies mutate to (parent, lastToken), and folds the
+// result back up through SetProp at each level on the way out - the same
+// write-back pattern WalkDescend uses so that a reallocation partway down
+// (e.g. a list insert growing past capacity) is still visible from the top.
+func applyAtPointer(container any, tokens []string, mutate func(parent any, lastToken string) (any, bool)) (any, bool) {
+	if len(tokens) == 1 {
+		return mutate(container, tokens[0])
+	}
+
+	child := GetProp(container, tokens[0])
+	if !IsNode(child) {
+		return container, false
+	}
+
+	newChild, ok := applyAtPointer(child, tokens[1:], mutate)
+	if !ok {
+		return container, false
+	}
+
+	return SetProp(container, tokens[0], newChild), true
+}
+
+// MergePatch applies a JSON Merge Patch (RFC 7396): wherever patch isn't an
+// object it replaces target outright, and wherever both are objects it
+// recurses member by member, deleting a member when its patch value is
+// null. It deliberately doesn't delegate array handling to Merge, which
+// merges same-kind lists element by element - RFC 7396 never merges
+// arrays, so a list anywhere in patch always replaces the corresponding
+// target list wholesale.
+func MergePatch(target, patch any) any {
+	if !IsMap(patch) {
+		return patch
+	}
+
+	out, ok := CloneFlags(target, nil).(map[string]any)
+	if !ok {
+		out = map[string]any{}
+	}
+
+	for _, kv := range Items(patch) {
+		key := StrKey(kv[0])
+		val := kv[1]
+
+		if val == nil {
+			delete(out, key)
+			continue
+		}
+
+		out[key] = MergePatch(out[key], val)
+	}
+
+	return out
+}
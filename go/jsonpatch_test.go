@@ -0,0 +1,131 @@
+package voxgigstruct_test
+
+import (
+	"testing"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+func TestDiffApplyPatchRoundTrip(t *testing.T) {
+	a := map[string]any{
+		"x":    float64(1),
+		"y":    map[string]any{"z": float64(2)},
+		"list": []any{float64(1), float64(2), float64(3)},
+	}
+	b := map[string]any{
+		"x":    float64(1),
+		"y":    map[string]any{"z": float64(3)},
+		"list": []any{float64(1), float64(3)},
+	}
+
+	ops := voxgigstruct.Diff(a, b)
+	if len(ops) == 0 {
+		t.Fatalf("expected at least one patch op")
+	}
+
+	out := voxgigstruct.ApplyPatch(a, ops)
+	if voxgigstruct.Stringify(out) != voxgigstruct.Stringify(b) {
+		t.Fatalf("expected patched a to equal b, got %v want %v", out, b)
+	}
+}
+
+func TestDiffListAvoidsSpuriousReplace(t *testing.T) {
+	a := []any{float64(1), float64(2), float64(3)}
+	b := []any{float64(1), float64(3)}
+
+	ops := voxgigstruct.Diff(a, b)
+	if len(ops) != 1 || ops[0].Op != "remove" {
+		t.Fatalf("expected a single remove op for a one-element list shrink, got %+v", ops)
+	}
+}
+
+func TestDiffNoOpsWhenEqual(t *testing.T) {
+	a := map[string]any{"x": float64(1)}
+	b := map[string]any{"x": float64(1)}
+
+	ops := voxgigstruct.Diff(a, b)
+	if len(ops) != 0 {
+		t.Fatalf("expected no ops for equal values, got %+v", ops)
+	}
+}
+
+func TestApplyPatchAddRemoveReplace(t *testing.T) {
+	root := map[string]any{"a": float64(1)}
+
+	out := voxgigstruct.ApplyPatch(root, []voxgigstruct.PatchOp{
+		{Op: "add", Path: "/b", Value: float64(2)},
+		{Op: "replace", Path: "/a", Value: float64(9)},
+		{Op: "remove", Path: "/b"},
+	})
+
+	m := out.(map[string]any)
+	if m["a"] != float64(9) {
+		t.Fatalf("expected a=9, got %+v", m)
+	}
+	if _, has := m["b"]; has {
+		t.Fatalf("expected b to be removed, got %+v", m)
+	}
+}
+
+func TestApplyPatchRollsBackOnFailedTest(t *testing.T) {
+	root := map[string]any{"a": float64(1)}
+
+	out := voxgigstruct.ApplyPatch(root, []voxgigstruct.PatchOp{
+		{Op: "add", Path: "/b", Value: float64(2)},
+		{Op: "test", Path: "/a", Value: float64(999)},
+	})
+
+	if voxgigstruct.Stringify(out) != voxgigstruct.Stringify(root) {
+		t.Fatalf("expected failed test op to roll back to the original root, got %+v", out)
+	}
+}
+
+func TestApplyPatchMoveAndCopy(t *testing.T) {
+	root := map[string]any{"a": float64(1)}
+
+	out := voxgigstruct.ApplyPatch(root, []voxgigstruct.PatchOp{
+		{Op: "copy", From: "/a", Path: "/b"},
+		{Op: "move", From: "/a", Path: "/c"},
+	})
+
+	m := out.(map[string]any)
+	if m["b"] != float64(1) || m["c"] != float64(1) {
+		t.Fatalf("expected b and c to both be 1, got %+v", m)
+	}
+	if _, has := m["a"]; has {
+		t.Fatalf("expected a to be moved away, got %+v", m)
+	}
+}
+
+func TestMergePatchDeletesNullMembers(t *testing.T) {
+	target := map[string]any{
+		"a": float64(1),
+		"b": map[string]any{"c": float64(2), "d": float64(3)},
+	}
+	patch := map[string]any{
+		"a": nil,
+		"b": map[string]any{"c": float64(20)},
+		"e": float64(5),
+	}
+
+	out := voxgigstruct.MergePatch(target, patch)
+
+	want := map[string]any{
+		"b": map[string]any{"c": float64(20), "d": float64(3)},
+		"e": float64(5),
+	}
+	if voxgigstruct.Stringify(out) != voxgigstruct.Stringify(want) {
+		t.Fatalf("expected %+v, got %+v", want, out)
+	}
+}
+
+func TestMergePatchReplacesArraysWholesale(t *testing.T) {
+	target := map[string]any{"list": []any{float64(1), float64(2), float64(3)}}
+	patch := map[string]any{"list": []any{float64(9)}}
+
+	out := voxgigstruct.MergePatch(target, patch).(map[string]any)
+	list := out["list"].([]any)
+	if len(list) != 1 || list[0] != float64(9) {
+		t.Fatalf("expected list to be replaced wholesale with [9], got %+v", list)
+	}
+}
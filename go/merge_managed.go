@@ -0,0 +1,173 @@
+package voxgigstruct
+
+import "strings"
+
+// ManagedPatch is one producer's contribution to a MergeManaged call: the
+// data it wants applied, which "manager" owns that data, and whether it
+// should force-overwrite fields already owned by a different manager.
+type ManagedPatch struct {
+	Manager string
+	Value   any
+	Force   bool
+}
+
+// FieldSet records, for every leaf field path touched by a MergeManaged
+// call, which manager currently owns it. Paths are joined with Pathify so
+// they stay comparable with Walk's []string path representation.
+type FieldSet struct {
+	Owners map[string]string // Pathify(path) -> manager
+}
+
+func newFieldSet() FieldSet {
+	return FieldSet{Owners: map[string]string{}}
+}
+
+// Paths returns the owned field paths, each split back into a []string.
+func (fs FieldSet) Paths() [][]string {
+	out := make([][]string, 0, len(fs.Owners))
+	for k := range fs.Owners {
+		if k == S_MT {
+			out = append(out, []string{})
+		} else {
+			out = append(out, strings.Split(k, S_DT))
+		}
+	}
+	return out
+}
+
+// Conflict records a field two managers disagree on. Path holds the raw
+// path segments; Conflict does no Pathify formatting of its own.
+type Conflict struct {
+	Path     []string
+	Manager  string // the manager that already owns the field
+	Existing any
+	Incoming any
+}
+
+// associativeKey is the field name used to merge list items by identity
+// rather than treating the whole list as a single atomically-owned unit.
+const associativeKey = "name"
+
+// MergeManaged merges a base tree with a sequence of ManagedPatch values,
+// Kubernetes server-side-apply style: every leaf field a patch touches is
+// recorded as owned by patch.Manager, and a field already owned by a
+// different manager with a conflicting value is left untouched (and
+// reported) unless Force is set. Lists of maps carrying a "name" field are
+// merged associatively, by that key; other lists are owned atomically, as
+// a single whole-list field. Associatively-merged list fields are
+// materialized in result as a map keyed by the associative key (not
+// reassembled into a list) so per-item ownership survives round-trips
+// through Extract.
+func MergeManaged(base any, patches []ManagedPatch) (any, FieldSet, []Conflict) {
+	result := Clone(base)
+	if result == nil {
+		result = map[string]any{}
+	}
+
+	owners := newFieldSet()
+	var conflicts []Conflict
+
+	for _, patch := range patches {
+		applyManagedPatch(result, patch, nil, &owners, &conflicts)
+	}
+
+	return result, owners, conflicts
+}
+
+func applyManagedPatch(result any, patch ManagedPatch, path []string, owners *FieldSet, conflicts *[]Conflict) {
+	if IsMap(patch.Value) {
+		for _, kv := range Items(patch.Value) {
+			k := StrKey(kv[0])
+			childPatch := ManagedPatch{Manager: patch.Manager, Value: kv[1], Force: patch.Force}
+			applyManagedPatch(result, childPatch, append(path, k), owners, conflicts)
+		}
+		return
+	}
+
+	if IsList(patch.Value) && isAssociativeList(patch.Value) {
+		for _, item := range patch.Value.([]any) {
+			name := StrKey(GetProp(item, associativeKey))
+			childPatch := ManagedPatch{Manager: patch.Manager, Value: item, Force: patch.Force}
+			applyManagedPatch(result, childPatch, append(path, name), owners, conflicts)
+		}
+		return
+	}
+
+	// Atomic leaf: a scalar, an atomic (non-associative) list, or an empty map.
+	setOwnedField(result, path, patch, owners, conflicts)
+}
+
+func isAssociativeList(val any) bool {
+	list, ok := val.([]any)
+	if !ok || len(list) == 0 {
+		return false
+	}
+	for _, item := range list {
+		if !IsMap(item) || !HasKey(item, associativeKey) {
+			return false
+		}
+	}
+	return true
+}
+
+func setOwnedField(result any, path []string, patch ManagedPatch, owners *FieldSet, conflicts *[]Conflict) {
+	pathKey := Pathify(path, 0)
+	existingOwner, owned := owners.Owners[pathKey]
+	existingVal := GetPath(path, result)
+
+	if owned && existingOwner != patch.Manager && !patch.Force && !deepEqualValue(existingVal, patch.Value) {
+		*conflicts = append(*conflicts, Conflict{
+			Path:     append([]string{}, path...),
+			Manager:  existingOwner,
+			Existing: existingVal,
+			Incoming: patch.Value,
+		})
+		return
+	}
+
+	setAtPath(result, path, patch.Value)
+	owners.Owners[pathKey] = patch.Manager
+}
+
+// setAtPath deep-sets value at path inside root, creating intermediate
+// maps as needed. root must already be a node (map or list).
+func setAtPath(root any, path []string, value any) {
+	if len(path) == 0 {
+		return
+	}
+
+	cur := root
+	for i := 0; i < len(path)-1; i++ {
+		next := GetProp(cur, path[i])
+		if !IsNode(next) {
+			next = map[string]any{}
+			SetProp(cur, path[i], next)
+		}
+		cur = next
+	}
+
+	SetProp(cur, path[len(path)-1], value)
+}
+
+func deepEqualValue(a, b any) bool {
+	return Stringify(a) == Stringify(b)
+}
+
+// Extract returns the subtree of tree consisting of exactly the fields
+// owned by manager in owners, so a producer can re-apply its own
+// contribution idempotently without clobbering fields owned by others.
+func Extract(tree any, owners FieldSet, manager string) any {
+	out := map[string]any{}
+	for pathKey, owner := range owners.Owners {
+		if owner != manager {
+			continue
+		}
+		var path []string
+		if pathKey != S_MT {
+			path = strings.Split(pathKey, S_DT)
+		}
+		val := GetPath(path, tree)
+		setAtPath(out, path, val)
+	}
+	return out
+}
@@ -0,0 +1,124 @@
+package voxgigstruct_test
+
+import (
+	"testing"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+func TestMergeManagedBasicOwnership(t *testing.T) {
+	base := map[string]any{}
+
+	patches := []voxgigstruct.ManagedPatch{
+		{Manager: "alice", Value: map[string]any{"color": "red"}},
+		{Manager: "bob", Value: map[string]any{"size": "large"}},
+	}
+
+	out, owners, conflicts := voxgigstruct.MergeManaged(base, patches)
+
+	if len(conflicts) != 0 {
+		t.Fatalf("expected no conflicts, got %+v", conflicts)
+	}
+
+	result := out.(map[string]any)
+	if result["color"] != "red" || result["size"] != "large" {
+		t.Fatalf("unexpected merged result: %+v", result)
+	}
+
+	if owners.Owners["color"] != "alice" || owners.Owners["size"] != "bob" {
+		t.Fatalf("unexpected ownership: %+v", owners.Owners)
+	}
+}
+
+func TestMergeManagedConflict(t *testing.T) {
+	base := map[string]any{}
+
+	patches := []voxgigstruct.ManagedPatch{
+		{Manager: "alice", Value: map[string]any{"color": "red"}},
+		{Manager: "bob", Value: map[string]any{"color": "blue"}},
+	}
+
+	out, _, conflicts := voxgigstruct.MergeManaged(base, patches)
+
+	if len(conflicts) != 1 {
+		t.Fatalf("expected exactly one conflict, got %+v", conflicts)
+	}
+	if conflicts[0].Manager != "alice" {
+		t.Fatalf("expected alice to be the existing owner, got %s", conflicts[0].Manager)
+	}
+
+	result := out.(map[string]any)
+	if result["color"] != "red" {
+		t.Fatalf("expected base owner's value to win on conflict, got %v", result["color"])
+	}
+}
+
+func TestMergeManagedForceOverride(t *testing.T) {
+	base := map[string]any{}
+
+	patches := []voxgigstruct.ManagedPatch{
+		{Manager: "alice", Value: map[string]any{"color": "red"}},
+		{Manager: "bob", Value: map[string]any{"color": "blue"}, Force: true},
+	}
+
+	out, owners, conflicts := voxgigstruct.MergeManaged(base, patches)
+
+	if len(conflicts) != 0 {
+		t.Fatalf("expected no conflicts with Force, got %+v", conflicts)
+	}
+
+	result := out.(map[string]any)
+	if result["color"] != "blue" {
+		t.Fatalf("expected forced value to win, got %v", result["color"])
+	}
+	if owners.Owners["color"] != "bob" {
+		t.Fatalf("expected bob to now own color, got %s", owners.Owners["color"])
+	}
+}
+
+func TestMergeManagedAssociativeList(t *testing.T) {
+	base := map[string]any{}
+
+	patches := []voxgigstruct.ManagedPatch{
+		{Manager: "alice", Value: map[string]any{
+			"items": []any{
+				map[string]any{"name": "a", "qty": float64(1)},
+			},
+		}},
+		{Manager: "bob", Value: map[string]any{
+			"items": []any{
+				map[string]any{"name": "b", "qty": float64(2)},
+			},
+		}},
+	}
+
+	out, _, conflicts := voxgigstruct.MergeManaged(base, patches)
+	if len(conflicts) != 0 {
+		t.Fatalf("expected no conflicts merging distinct associative items, got %+v", conflicts)
+	}
+
+	result := out.(map[string]any)
+	items := result["items"].(map[string]any)
+	if items["a"] == nil || items["b"] == nil {
+		t.Fatalf("expected both associative items present, got %+v", items)
+	}
+}
+
+func TestExtractReturnsOnlyOwnedFields(t *testing.T) {
+	base := map[string]any{}
+
+	patches := []voxgigstruct.ManagedPatch{
+		{Manager: "alice", Value: map[string]any{"color": "red"}},
+		{Manager: "bob", Value: map[string]any{"size": "large"}},
+	}
+
+	out, owners, _ := voxgigstruct.MergeManaged(base, patches)
+
+	aliceView := voxgigstruct.Extract(out, owners, "alice").(map[string]any)
+	if aliceView["color"] != "red" {
+		t.Fatalf("expected alice's extract to contain color, got %+v", aliceView)
+	}
+	if _, has := aliceView["size"]; has {
+		t.Fatalf("expected alice's extract to not contain bob's field, got %+v", aliceView)
+	}
+}
@@ -0,0 +1,186 @@
+package voxgigstruct
+
+import (
+	"container/list"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// segKind classifies one segment of a compiled Path, so a hot traversal
+// loop can skip retrying strconv.Atoi on a segment already known to be a
+// plain map key (see getPropFast).
+type segKind int
+
+const (
+	segString segKind = iota
+	segInt
+)
+
+// Path is a pre-parsed, validated path, produced by CompilePath. Splitting
+// a dotted string like "a.b.c" and sniffing which segments look like list
+// indexes is wasted work when the same path is resolved against every
+// element of a large list (Inject/Transform's most common hot loop) -
+// compiling it once up front avoids repeating that work on every call to
+// GetPath/GetPathState/SetProp/HasKey.
+type Path struct {
+	Parts    []string
+	Relative bool // true if the first segment is empty (a leading-dot path)
+
+	kinds   []segKind
+	intVals []int // valid where kinds[i] == segInt
+}
+
+// CompilePath compiles spec - a dotted string, a []string, or an already-
+// compiled *Path (returned as-is) - into a *Path. A string starting with
+// "/" or "$" is a JSON Pointer/JSONPath query, not a dotted Path; use
+// GetPathQuery for those instead.
+func CompilePath(spec any) (*Path, error) {
+	switch v := spec.(type) {
+	case *Path:
+		return v, nil
+
+	case Path:
+		p := v
+		return &p, nil
+
+	case []string:
+		return compilePathParts(append([]string{}, v...)), nil
+
+	case string:
+		if isDialectPath(v) {
+			return nil, fmt.Errorf(
+				"voxgigstruct: %q is a JSON Pointer/JSONPath query, not a dotted Path - use GetPathQuery", v)
+		}
+		if v == S_MT {
+			return compilePathParts([]string{S_MT}), nil
+		}
+		return compilePathParts(strings.Split(v, S_DT)), nil
+
+	default:
+		if IsList(spec) {
+			return compilePathParts(_resolveStrings(spec.([]any))), nil
+		}
+		return nil, fmt.Errorf("voxgigstruct: cannot compile path of type %T", spec)
+	}
+}
+
+func compilePathParts(parts []string) *Path {
+	kinds := make([]segKind, len(parts))
+	intVals := make([]int, len(parts))
+
+	for i, p := range parts {
+		if n, err := strconv.Atoi(p); err == nil {
+			kinds[i] = segInt
+			intVals[i] = n
+		} else {
+			kinds[i] = segString
+		}
+	}
+
+	return &Path{
+		Parts:    parts,
+		Relative: len(parts) > 0 && parts[0] == S_MT,
+		kinds:    kinds,
+		intVals:  intVals,
+	}
+}
+
+// getPropFast resolves path segment i of val against cpath, using the
+// segment's precomputed kind to skip GetProp's strconv.Atoi retry when
+// descending into a list. Map indexing always uses the segment's original
+// string form, so a literal key like "007" still means exactly that and
+// not the int 7 - only list indexing benefits from the precomputed int.
+func getPropFast(val any, cpath *Path, i int) any {
+	if cpath.kinds[i] == segInt && IsList(val) {
+		return GetProp(val, cpath.intVals[i])
+	}
+	return GetProp(val, cpath.Parts[i])
+}
+
+// pathCacheEntry is one entry in the package-level compiled-Path LRU.
+type pathCacheEntry struct {
+	key string
+	val *Path
+}
+
+var (
+	pathCacheMu   sync.Mutex
+	pathCacheSize = 512
+	pathCacheList = list.New()
+	pathCacheMap  = map[string]*list.Element{}
+)
+
+// SetPathCacheSize configures the maximum number of compiled Paths kept by
+// CompileCachedPath's package-level LRU (used internally by GetPathState
+// and therefore by Inject/Transform's per-item path resolution). A size of
+// 0 or less disables caching - CompileCachedPath then compiles on every
+// call. Safe to call concurrently with path resolution.
+func SetPathCacheSize(size int) {
+	pathCacheMu.Lock()
+	defer pathCacheMu.Unlock()
+
+	pathCacheSize = size
+	for pathCacheSize > 0 && pathCacheList.Len() > pathCacheSize {
+		evictOldestPathLocked()
+	}
+	if pathCacheSize <= 0 {
+		pathCacheList.Init()
+		pathCacheMap = map[string]*list.Element{}
+	}
+}
+
+func evictOldestPathLocked() {
+	oldest := pathCacheList.Back()
+	if oldest == nil {
+		return
+	}
+	pathCacheList.Remove(oldest)
+	delete(pathCacheMap, oldest.Value.(*pathCacheEntry).key)
+}
+
+// CompileCachedPath compiles a dotted path string into a *Path, reusing a
+// previously-compiled result from the package-level LRU when the same raw
+// string was seen before (see SetPathCacheSize). This is what
+// GetPathState calls for every plain string path, so a path like "a.b.c"
+// repeated across every element of a large list is only split and
+// kind-sniffed once rather than on every element.
+func CompileCachedPath(raw string) (*Path, error) {
+	pathCacheMu.Lock()
+	if el, ok := pathCacheMap[raw]; ok {
+		pathCacheList.MoveToFront(el)
+		entry := el.Value.(*pathCacheEntry)
+		pathCacheMu.Unlock()
+		return entry.val, nil
+	}
+	pathCacheMu.Unlock()
+
+	p, err := CompilePath(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	pathCacheMu.Lock()
+	defer pathCacheMu.Unlock()
+
+	if pathCacheSize <= 0 {
+		return p, nil
+	}
+
+	// Another goroutine may have compiled and cached the same raw string
+	// while this one held no lock.
+	if el, ok := pathCacheMap[raw]; ok {
+		pathCacheList.MoveToFront(el)
+		return el.Value.(*pathCacheEntry).val, nil
+	}
+
+	el := pathCacheList.PushFront(&pathCacheEntry{key: raw, val: p})
+	pathCacheMap[raw] = el
+
+	for pathCacheList.Len() > pathCacheSize {
+		evictOldestPathLocked()
+	}
+
+	return p, nil
+}
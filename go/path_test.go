@@ -0,0 +1,170 @@
+package voxgigstruct_test
+
+import (
+	"strconv"
+	"testing"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+func TestCompilePathDottedString(t *testing.T) {
+	p, err := voxgigstruct.CompilePath("a.b.2")
+	if err != nil {
+		t.Fatalf("CompilePath: %v", err)
+	}
+	if len(p.Parts) != 3 || p.Parts[0] != "a" || p.Parts[2] != "2" {
+		t.Fatalf("unexpected parts: %+v", p.Parts)
+	}
+	if p.Relative {
+		t.Fatalf("expected a.b.2 to not be relative")
+	}
+}
+
+func TestCompilePathRelative(t *testing.T) {
+	p, err := voxgigstruct.CompilePath(".a.b")
+	if err != nil {
+		t.Fatalf("CompilePath: %v", err)
+	}
+	if !p.Relative {
+		t.Fatalf("expected leading-dot path to be relative")
+	}
+}
+
+func TestCompilePathRejectsDialectQuery(t *testing.T) {
+	if _, err := voxgigstruct.CompilePath("/a/b"); err == nil {
+		t.Fatalf("expected an error compiling a JSON Pointer as a dotted Path")
+	}
+	if _, err := voxgigstruct.CompilePath("$.a.b"); err == nil {
+		t.Fatalf("expected an error compiling a JSONPath query as a dotted Path")
+	}
+}
+
+func TestGetPathAcceptsCompiledPath(t *testing.T) {
+	store := map[string]any{"a": map[string]any{"b": []any{float64(10), float64(20)}}}
+
+	p, err := voxgigstruct.CompilePath("a.b.1")
+	if err != nil {
+		t.Fatalf("CompilePath: %v", err)
+	}
+
+	if out := voxgigstruct.GetPath(p, store); out != float64(20) {
+		t.Fatalf("expected 20, got %v", out)
+	}
+}
+
+func TestSetPropWithCompiledPathCreatesIntermediates(t *testing.T) {
+	root := map[string]any{}
+	p, err := voxgigstruct.CompilePath("a.b.c")
+	if err != nil {
+		t.Fatalf("CompilePath: %v", err)
+	}
+
+	voxgigstruct.SetProp(root, p, "v")
+
+	if voxgigstruct.GetPath("a.b.c", root) != "v" {
+		t.Fatalf("expected a.b.c to be set, got %+v", root)
+	}
+}
+
+func TestHasKeyWithCompiledPath(t *testing.T) {
+	store := map[string]any{"a": map[string]any{"b": float64(1)}}
+
+	p, err := voxgigstruct.CompilePath("a.b")
+	if err != nil {
+		t.Fatalf("CompilePath: %v", err)
+	}
+	if !voxgigstruct.HasKey(store, p) {
+		t.Fatalf("expected a.b to be present")
+	}
+
+	missing, err := voxgigstruct.CompilePath("a.nope")
+	if err != nil {
+		t.Fatalf("CompilePath: %v", err)
+	}
+	if voxgigstruct.HasKey(store, missing) {
+		t.Fatalf("expected a.nope to be absent")
+	}
+}
+
+func TestGetPathPreservesZeroPaddedMapKeys(t *testing.T) {
+	// A map key that merely looks numeric must still be looked up by its
+	// literal string form - only list indexing gets the precomputed int
+	// fast path (see getPropFast in path.go).
+	store := map[string]any{"007": "bond"}
+	if out := voxgigstruct.GetPath("007", store); out != "bond" {
+		t.Fatalf("expected zero-padded map key lookup to work, got %v", out)
+	}
+}
+
+func TestCompileCachedPathReusesCompiledResult(t *testing.T) {
+	a, err := voxgigstruct.CompileCachedPath("cache.test.path")
+	if err != nil {
+		t.Fatalf("CompileCachedPath: %v", err)
+	}
+	b, err := voxgigstruct.CompileCachedPath("cache.test.path")
+	if err != nil {
+		t.Fatalf("CompileCachedPath: %v", err)
+	}
+	if a != b {
+		t.Fatalf("expected the same *Path instance to be returned from cache")
+	}
+}
+
+func TestSetPathCacheSizeEvicts(t *testing.T) {
+	voxgigstruct.SetPathCacheSize(1)
+	defer voxgigstruct.SetPathCacheSize(512)
+
+	first, _ := voxgigstruct.CompileCachedPath("evict.me.first")
+	_, _ = voxgigstruct.CompileCachedPath("evict.me.second")
+	again, _ := voxgigstruct.CompileCachedPath("evict.me.first")
+
+	if first == again {
+		t.Fatalf("expected the first path to have been evicted from a size-1 cache")
+	}
+}
+
+func deepNestedStore(depth int) map[string]any {
+	store := map[string]any{}
+	cur := store
+	for i := 0; i < depth; i++ {
+		next := map[string]any{}
+		cur[strconv.Itoa(i)] = next
+		cur = next
+	}
+	cur["leaf"] = float64(42)
+	return store
+}
+
+func deepNestedPath(depth int) string {
+	path := ""
+	for i := 0; i < depth; i++ {
+		if i > 0 {
+			path += "."
+		}
+		path += strconv.Itoa(i)
+	}
+	return path + ".leaf"
+}
+
+func BenchmarkGetPathAdHocString(b *testing.B) {
+	store := deepNestedStore(50)
+	path := deepNestedPath(50)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		voxgigstruct.GetPath(path, store)
+	}
+}
+
+func BenchmarkGetPathPrecompiled(b *testing.B) {
+	store := deepNestedStore(50)
+	p, err := voxgigstruct.CompilePath(deepNestedPath(50))
+	if err != nil {
+		b.Fatalf("CompilePath: %v", err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		voxgigstruct.GetPath(p, store)
+	}
+}
@@ -0,0 +1,454 @@
+package voxgigstruct
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/voxgig/struct/expr"
+)
+
+// Match is one result of GetPathQuery: the matched value, and the concrete
+// path it was found at, in the same []string shape Walk/Pathify use.
+type Match struct {
+	Value any
+	Path  []string
+}
+
+// GetPathQuery runs a query against store, selecting either RFC 6901 JSON
+// Pointer syntax ("/foo/0/bar", with "~0"/"~1" escaping) for a single
+// result, or a JSONPath subset ("$.store.book[*].author", "$..price",
+// "$.items[?(@.qty > 2)].name") for potentially many results.
+func GetPathQuery(query string, store any) ([]Match, error) {
+	if strings.HasPrefix(query, "$") {
+		return jsonPathQuery(query, store)
+	}
+	return jsonPointerQuery(query, store)
+}
+
+// SetPathQuery evaluates query against store and writes value to every
+// matched location, returning the matches that were updated.
+func SetPathQuery(query string, store any, value any) ([]Match, error) {
+	matches, err := GetPathQuery(query, store)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range matches {
+		setAtPath(store, m.Path, value)
+	}
+	return matches, nil
+}
+
+// isDialectPath reports whether a string path is one of the root-anchored
+// query dialects (RFC 6901 JSON Pointer or JSONPath) GetPathState dispatches
+// to GetPathQuery, rather than the plain dotted/relative path syntax.
+//
+// Only "$", "$." and "$[" are treated as JSONPath - a bare "$NAME" (no '.'
+// or '[' following) is left alone, since that's the transform-function
+// lookup syntax (store["$KEY"], store["$MERGE"], etc.) _injectStr/transform
+// already resolve through the plain path branch.
+func isDialectPath(path string) bool {
+	if strings.HasPrefix(path, "/") {
+		return true
+	}
+	return path == "$" || strings.HasPrefix(path, "$.") || strings.HasPrefix(path, "$[")
+}
+
+// resolveDialectPath runs path (a JSON Pointer or JSONPath string) against
+// store via GetPathQuery, returning the first match's value, or nil if the
+// query errors or matches nothing.
+func resolveDialectPath(path string, store any) any {
+	matches, err := GetPathQuery(path, store)
+	if err != nil || len(matches) == 0 {
+		return nil
+	}
+	return matches[0].Value
+}
+
+// GetPathJSONPointer resolves an RFC 6901 JSON Pointer against store,
+// returning the matched value, or nil if the pointer doesn't resolve.
+func GetPathJSONPointer(ptr string, store any) any {
+	matches, err := jsonPointerQuery(ptr, store)
+	if err != nil || len(matches) == 0 {
+		return nil
+	}
+	return matches[0].Value
+}
+
+// SetPropJSONPointer deep-sets value at the location an RFC 6901 JSON
+// Pointer addresses inside root, creating intermediate maps as needed (see
+// setAtPath), and returns root.
+func SetPropJSONPointer(root any, ptr string, val any) any {
+	setAtPath(root, jsonPointerTokens(ptr), val)
+	return root
+}
+
+// jsonPointerTokens splits a JSON Pointer into its unescaped reference
+// tokens, without requiring (unlike jsonPointerQuery) that they already
+// resolve - SetPropJSONPointer uses this to create intermediate nodes.
+func jsonPointerTokens(ptr string) []string {
+	if ptr == "" || ptr == "/" {
+		return nil
+	}
+	tokens := strings.Split(strings.TrimPrefix(ptr, "/"), "/")
+	for i, tok := range tokens {
+		tokens[i] = unescapePointerToken(tok)
+	}
+	return tokens
+}
+
+// FindAll resolves a JSONPath or JSON Pointer query against store and
+// returns every matched value (see GetPathQuery for the path-aware form).
+func FindAll(jp string, store any) []any {
+	matches, err := GetPathQuery(jp, store)
+	if err != nil {
+		return nil
+	}
+	out := make([]any, len(matches))
+	for i, m := range matches {
+		out[i] = m.Value
+	}
+	return out
+}
+
+// ---------------------------------------------------------------------
+// JSON Pointer (RFC 6901)
+
+func jsonPointerQuery(pointer string, store any) ([]Match, error) {
+	if pointer == "" {
+		return []Match{{Value: store, Path: []string{}}}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("voxgigstruct: invalid json pointer %q: must start with '/'", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	val := store
+	path := make([]string, 0, len(tokens))
+
+	for _, tok := range tokens {
+		tok = unescapePointerToken(tok)
+		path = append(path, tok)
+
+		if IsList(val) {
+			i, err := strconv.Atoi(tok)
+			if err != nil {
+				return nil, fmt.Errorf("voxgigstruct: json pointer %q: %q is not a valid array index", pointer, tok)
+			}
+			val = GetProp(val, i)
+		} else {
+			val = GetProp(val, tok)
+		}
+
+		if val == nil {
+			return nil, fmt.Errorf("voxgigstruct: json pointer %q: no value at %s", pointer, Pathify(path, 0))
+		}
+	}
+
+	return []Match{{Value: val, Path: path}}, nil
+}
+
+func unescapePointerToken(tok string) string {
+	tok = strings.ReplaceAll(tok, "~1", "/")
+	tok = strings.ReplaceAll(tok, "~0", "~")
+	return tok
+}
+
+func escapePointerToken(tok string) string {
+	tok = strings.ReplaceAll(tok, "~", "~0")
+	tok = strings.ReplaceAll(tok, "/", "~1")
+	return tok
+}
+
+// ---------------------------------------------------------------------
+// JSONPath (subset)
+
+type pathPair struct {
+	value any
+	path  []string
+}
+
+type pathStep func(pairs []pathPair) ([]pathPair, error)
+
+func jsonPathQuery(query string, store any) ([]Match, error) {
+	steps, err := compileJSONPath(query)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := []pathPair{{value: store, path: []string{}}}
+	for _, step := range steps {
+		pairs, err = step(pairs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	matches := make([]Match, len(pairs))
+	for i, p := range pairs {
+		matches[i] = Match{Value: p.value, Path: p.path}
+	}
+	return matches, nil
+}
+
+func compileJSONPath(query string) ([]pathStep, error) {
+	if !strings.HasPrefix(query, "$") {
+		return nil, fmt.Errorf("voxgigstruct: invalid jsonpath %q: must start with '$'", query)
+	}
+
+	var steps []pathStep
+	i := 1
+	for i < len(query) {
+		switch {
+		case strings.HasPrefix(query[i:], ".."):
+			i += 2
+			name, n := scanIdent(query[i:])
+			if n == 0 {
+				return nil, fmt.Errorf("voxgigstruct: invalid jsonpath %q: expected identifier after '..'", query)
+			}
+			i += n
+			steps = append(steps, recursiveChildStep(name))
+
+		case query[i] == '.':
+			i++
+			name, n := scanIdent(query[i:])
+			if n == 0 {
+				return nil, fmt.Errorf("voxgigstruct: invalid jsonpath %q: expected identifier after '.'", query)
+			}
+			i += n
+			steps = append(steps, childStep(name))
+
+		case query[i] == '[':
+			end := strings.IndexByte(query[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("voxgigstruct: invalid jsonpath %q: unterminated '['", query)
+			}
+			content := query[i+1 : i+end]
+			i += end + 1
+
+			step, err := compileBracket(content)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+
+		default:
+			return nil, fmt.Errorf("voxgigstruct: invalid jsonpath %q: unexpected character %q", query, string(query[i]))
+		}
+	}
+
+	return steps, nil
+}
+
+func scanIdent(s string) (string, int) {
+	n := 0
+	for n < len(s) && (isIdentRune(s[n])) {
+		n++
+	}
+	return s[:n], n
+}
+
+func isIdentRune(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func compileBracket(content string) (pathStep, error) {
+	content = strings.TrimSpace(content)
+
+	if content == "*" {
+		return wildcardStep(), nil
+	}
+
+	if strings.HasPrefix(content, "?(") && strings.HasSuffix(content, ")") {
+		pred := content[2 : len(content)-1]
+		return filterChildrenStep(pred)
+	}
+
+	if (strings.HasPrefix(content, "'") && strings.HasSuffix(content, "'")) ||
+		(strings.HasPrefix(content, "\"") && strings.HasSuffix(content, "\"")) {
+		return childStep(content[1 : len(content)-1]), nil
+	}
+
+	if strings.Contains(content, ":") {
+		return sliceStep(content)
+	}
+
+	if idx, err := strconv.Atoi(content); err == nil {
+		return indexStep(idx), nil
+	}
+
+	return nil, fmt.Errorf("voxgigstruct: invalid jsonpath bracket content %q", content)
+}
+
+// sliceStep implements Python-style list slicing, e.g. "[1:3]": start
+// defaults to 0, end defaults to the list's length and is exclusive,
+// negative values count back from the end.
+func sliceStep(content string) (pathStep, error) {
+	bounds := strings.SplitN(content, ":", 2)
+	startStr, endStr := strings.TrimSpace(bounds[0]), strings.TrimSpace(bounds[1])
+
+	return func(pairs []pathPair) ([]pathPair, error) {
+		var out []pathPair
+		for _, p := range pairs {
+			arr, ok := p.value.([]any)
+			if !ok {
+				continue
+			}
+
+			start, end := 0, len(arr)
+			if startStr != "" {
+				v, err := strconv.Atoi(startStr)
+				if err != nil {
+					return nil, fmt.Errorf("voxgigstruct: invalid jsonpath slice %q", content)
+				}
+				start = v
+			}
+			if endStr != "" {
+				v, err := strconv.Atoi(endStr)
+				if err != nil {
+					return nil, fmt.Errorf("voxgigstruct: invalid jsonpath slice %q", content)
+				}
+				end = v
+			}
+
+			start = clampSliceIndex(start, len(arr))
+			end = clampSliceIndex(end, len(arr))
+
+			for i := start; i < end; i++ {
+				out = append(out, pathPair{value: arr[i], path: append(append([]string{}, p.path...), strconv.Itoa(i))})
+			}
+		}
+		return out, nil
+	}, nil
+}
+
+func clampSliceIndex(i, length int) int {
+	if i < 0 {
+		i += length
+	}
+	if i < 0 {
+		return 0
+	}
+	if i > length {
+		return length
+	}
+	return i
+}
+
+func childStep(name string) pathStep {
+	return func(pairs []pathPair) ([]pathPair, error) {
+		var out []pathPair
+		for _, p := range pairs {
+			if !IsMap(p.value) {
+				continue
+			}
+			child := GetProp(p.value, name)
+			if child == nil {
+				continue
+			}
+			out = append(out, pathPair{value: child, path: append(append([]string{}, p.path...), name)})
+		}
+		return out, nil
+	}
+}
+
+func indexStep(idx int) pathStep {
+	return func(pairs []pathPair) ([]pathPair, error) {
+		var out []pathPair
+		for _, p := range pairs {
+			if !IsList(p.value) {
+				continue
+			}
+			child := GetProp(p.value, idx)
+			if child == nil {
+				continue
+			}
+			out = append(out, pathPair{value: child, path: append(append([]string{}, p.path...), strconv.Itoa(idx))})
+		}
+		return out, nil
+	}
+}
+
+func wildcardStep() pathStep {
+	return func(pairs []pathPair) ([]pathPair, error) {
+		var out []pathPair
+		for _, p := range pairs {
+			out = append(out, childrenOf(p)...)
+		}
+		return out, nil
+	}
+}
+
+func childrenOf(p pathPair) []pathPair {
+	var out []pathPair
+	for _, kv := range Items(p.value) {
+		k := StrKey(kv[0])
+		out = append(out, pathPair{value: kv[1], path: append(append([]string{}, p.path...), k)})
+	}
+	return out
+}
+
+// recursiveChildStep implements "..name": every descendant (at any depth,
+// including the current level) with key name.
+func recursiveChildStep(name string) pathStep {
+	return func(pairs []pathPair) ([]pathPair, error) {
+		var out []pathPair
+		for _, p := range pairs {
+			collectRecursiveChild(p, name, &out)
+		}
+		return out, nil
+	}
+}
+
+func collectRecursiveChild(p pathPair, name string, out *[]pathPair) {
+	if IsMap(p.value) {
+		if child := GetProp(p.value, name); child != nil {
+			*out = append(*out, pathPair{value: child, path: append(append([]string{}, p.path...), name)})
+		}
+	}
+	for _, kv := range Items(p.value) {
+		k := StrKey(kv[0])
+		collectRecursiveChild(pathPair{value: kv[1], path: append(append([]string{}, p.path...), k)}, name, out)
+	}
+}
+
+// filterChildrenStep implements "[?(@.field > 1)]": expand the children of
+// each current node, keeping only those satisfying the predicate, which is
+// compiled and run with the expression evaluator proposed for
+// Transform/Inject - "@" denotes the candidate child node.
+func filterChildrenStep(predSrc string) (pathStep, error) {
+	prog, err := Expr(translatePredicate(predSrc))
+	if err != nil {
+		return nil, fmt.Errorf("voxgigstruct: invalid jsonpath filter %q: %w", predSrc, err)
+	}
+
+	return func(pairs []pathPair) ([]pathPair, error) {
+		var out []pathPair
+		for _, p := range pairs {
+			for _, child := range childrenOf(p) {
+				res, err := prog.Eval(expr.EvalContext{Current: child.value, Path: child.path})
+				if err != nil {
+					return nil, err
+				}
+				if truthyMatch(res) {
+					out = append(out, child)
+				}
+			}
+		}
+		return out, nil
+	}, nil
+}
+
+// translatePredicate rewrites JSONPath's "@" current-node sigil into plain
+// field access, since the expression evaluator already resolves bare
+// identifiers against EvalContext.Current.
+func translatePredicate(pred string) string {
+	pred = strings.ReplaceAll(pred, "@.", "")
+	pred = strings.ReplaceAll(pred, "@", "")
+	return pred
+}
+
+func truthyMatch(v any) bool {
+	b, ok := v.(bool)
+	return ok && b
+}
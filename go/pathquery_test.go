@@ -0,0 +1,134 @@
+package voxgigstruct_test
+
+import (
+	"testing"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+func sampleStore() map[string]any {
+	return map[string]any{
+		"store": map[string]any{
+			"book": []any{
+				map[string]any{"author": "A", "price": float64(10)},
+				map[string]any{"author": "B", "price": float64(20)},
+			},
+			"bicycle": map[string]any{"price": float64(100)},
+		},
+	}
+}
+
+func TestGetPathQueryJSONPointer(t *testing.T) {
+	matches, err := voxgigstruct.GetPathQuery("/store/book/0/author", sampleStore())
+	if err != nil {
+		t.Fatalf("GetPathQuery: %v", err)
+	}
+	if len(matches) != 1 || matches[0].Value != "A" {
+		t.Fatalf("unexpected matches: %+v", matches)
+	}
+}
+
+func TestGetPathQueryJSONPathWildcard(t *testing.T) {
+	matches, err := voxgigstruct.GetPathQuery("$.store.book[*].author", sampleStore())
+	if err != nil {
+		t.Fatalf("GetPathQuery: %v", err)
+	}
+	if len(matches) != 2 || matches[0].Value != "A" || matches[1].Value != "B" {
+		t.Fatalf("unexpected matches: %+v", matches)
+	}
+}
+
+func TestGetPathQueryJSONPathRecursive(t *testing.T) {
+	matches, err := voxgigstruct.GetPathQuery("$..price", sampleStore())
+	if err != nil {
+		t.Fatalf("GetPathQuery: %v", err)
+	}
+	if len(matches) != 3 {
+		t.Fatalf("expected 3 price matches, got %+v", matches)
+	}
+}
+
+func TestGetPathQueryJSONPathFilter(t *testing.T) {
+	matches, err := voxgigstruct.GetPathQuery("$.store.book[?(@.price > 15)].author", sampleStore())
+	if err != nil {
+		t.Fatalf("GetPathQuery: %v", err)
+	}
+	if len(matches) != 1 || matches[0].Value != "B" {
+		t.Fatalf("unexpected matches: %+v", matches)
+	}
+}
+
+func TestSetPathQueryWritesAllMatches(t *testing.T) {
+	store := sampleStore()
+	matches, err := voxgigstruct.SetPathQuery("$.store.book[*].price", store, float64(0))
+	if err != nil {
+		t.Fatalf("SetPathQuery: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 matches, got %+v", matches)
+	}
+
+	books := voxgigstruct.GetPath("store.book", store).([]any)
+	for _, b := range books {
+		if voxgigstruct.GetProp(b, "price") != float64(0) {
+			t.Fatalf("expected price reset to 0, got %+v", b)
+		}
+	}
+}
+
+func TestGetPathQueryJSONPathSlice(t *testing.T) {
+	matches, err := voxgigstruct.GetPathQuery("$.store.book[0:1]", sampleStore())
+	if err != nil {
+		t.Fatalf("GetPathQuery: %v", err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("expected 1 match, got %+v", matches)
+	}
+	if voxgigstruct.GetProp(matches[0].Value, "author") != "A" {
+		t.Fatalf("unexpected slice match: %+v", matches[0].Value)
+	}
+}
+
+func TestGetPathDispatchesJSONPointer(t *testing.T) {
+	if out := voxgigstruct.GetPath("/store/bicycle/price", sampleStore()); out != float64(100) {
+		t.Fatalf("expected 100, got %v", out)
+	}
+}
+
+func TestGetPathDispatchesJSONPath(t *testing.T) {
+	if out := voxgigstruct.GetPath("$.store.book[1].author", sampleStore()); out != "B" {
+		t.Fatalf("expected B, got %v", out)
+	}
+}
+
+func TestGetPathLeavesDollarTransformNameAlone(t *testing.T) {
+	store := map[string]any{"$KEY": "marker"}
+	if out := voxgigstruct.GetPath("$KEY", store); out != "marker" {
+		t.Fatalf("expected $KEY to resolve as a plain map key, got %v", out)
+	}
+}
+
+func TestGetPathJSONPointer(t *testing.T) {
+	if out := voxgigstruct.GetPathJSONPointer("/store/book/1/author", sampleStore()); out != "B" {
+		t.Fatalf("expected B, got %v", out)
+	}
+	if out := voxgigstruct.GetPathJSONPointer("/nope", sampleStore()); out != nil {
+		t.Fatalf("expected nil for an unresolved pointer, got %v", out)
+	}
+}
+
+func TestSetPropJSONPointerCreatesIntermediates(t *testing.T) {
+	root := map[string]any{}
+	voxgigstruct.SetPropJSONPointer(root, "/a/b/c", "v")
+
+	if voxgigstruct.GetPath("a.b.c", root) != "v" {
+		t.Fatalf("expected a.b.c to be set, got %+v", root)
+	}
+}
+
+func TestFindAll(t *testing.T) {
+	out := voxgigstruct.FindAll("$..price", sampleStore())
+	if len(out) != 3 {
+		t.Fatalf("expected 3 prices, got %+v", out)
+	}
+}
@@ -0,0 +1,175 @@
+package voxgigstruct
+
+import (
+	"fmt"
+	"strings"
+)
+
+// builtinTransforms lists every directive already wired into
+// TransformModify's own store map, so Registry can refuse to silently
+// shadow one of them.
+var builtinTransforms = map[string]bool{
+	"$DELETE": true, "$COPY": true, "$KEY": true, "$META": true, "$MERGE": true,
+	"$EACH": true, "$PACK": true,
+	"$FILTER": true, "$SORT": true, "$GROUP": true, "$REDUCE": true, "$FLATTEN": true,
+	"$BT": true, "$DS": true, "$WHEN": true,
+}
+
+// builtinValidators lists every directive already wired into
+// validateCollect's own store map.
+var builtinValidators = map[string]bool{
+	"$STRING": true, "$NUMBER": true, "$BOOLEAN": true, "$OBJECT": true,
+	"$ARRAY": true, "$FUNCTION": true, "$ANY": true, "$CHILD": true,
+	"$ONE": true, "$EXACT": true,
+	"$REGEX": true, "$ENUM": true, "$RANGE": true, "$LENGTH": true, "$FORMAT": true,
+	"$TYPE": true,
+}
+
+// Registry is a user-managed collection of transform and validator
+// directives, kept separate from TransformModify/validateCollect's own
+// built-in store maps so a downstream package can ship domain-specific
+// directives (e.g. $UUID, $HASH, $JWT) without forking or editing this
+// package. A Registry is not safe for concurrent registration; build it
+// up once, then pass it to TransformWith as needed.
+type Registry struct {
+	transforms map[string]Injector
+	validators map[string]Injector
+
+	// AllowOverride, if set, lets Register* redefine a built-in directive
+	// name or a name already registered on this Registry. False by
+	// default, so a typo that collides with an existing name fails loudly
+	// instead of silently shadowing it.
+	AllowOverride bool
+}
+
+// NewRegistry returns an empty Registry ready for Register*/Extend calls.
+func NewRegistry() *Registry {
+	return &Registry{
+		transforms: map[string]Injector{},
+		validators: map[string]Injector{},
+	}
+}
+
+// checkName validates that name may be registered: it must begin with
+// S_DS ("$"), and - unless AllowOverride is set - must not collide with a
+// built-in directive or a name already present in own.
+func (r *Registry) checkName(name string, builtins map[string]bool, own map[string]Injector) error {
+	if !strings.HasPrefix(name, S_DS) {
+		return fmt.Errorf("plugin: %q must begin with %q", name, S_DS)
+	}
+	if !r.AllowOverride {
+		if builtins[name] {
+			return fmt.Errorf("plugin: %q redefines a built-in directive; set AllowOverride to allow this", name)
+		}
+		if _, has := own[name]; has {
+			return fmt.Errorf("plugin: %q is already registered; set AllowOverride to allow this", name)
+		}
+	}
+	return nil
+}
+
+// RegisterTransform adds fn as a transform directive under name (e.g.
+// "$UUID"), usable in a spec passed to TransformWith anywhere a built-in
+// directive like $COPY or $MERGE is.
+func (r *Registry) RegisterTransform(name string, fn Injector) error {
+	if err := r.checkName(name, builtinTransforms, r.transforms); err != nil {
+		return err
+	}
+	r.transforms[name] = fn
+	return nil
+}
+
+// RegisterValidator adds fn as a validation directive under name (e.g.
+// "$POSITIVE"). TransformWith makes it available in its spec alongside the
+// registered transforms; ValidateCollect does not read a Registry.
+func (r *Registry) RegisterValidator(name string, fn Injector) error {
+	if err := r.checkName(name, builtinValidators, r.validators); err != nil {
+		return err
+	}
+	r.validators[name] = fn
+	return nil
+}
+
+// RegisterFunction wraps fn - a func(args ...any) (any, error) - into a
+// transform directive registered under name. Used bare (e.g. as the value
+// '`$UUID`') fn is called with no arguments; used as the head of a
+// directive list (e.g. ['`$HASH`', 'md5']) fn receives the list's
+// remaining elements as args. An error returned by fn is recorded via the
+// same addErr mechanism a built-in validator uses, rather than panicking.
+func (r *Registry) RegisterFunction(name string, fn any) error {
+	pf, ok := fn.(func(args ...any) (any, error))
+	if !ok {
+		return fmt.Errorf("plugin: RegisterFunction %q: fn must be func(args ...any) (any, error), got %T", name, fn)
+	}
+	return r.RegisterTransform(name, wrapPluginFunc(name, pf))
+}
+
+// wrapPluginFunc adapts a plain func(args ...any) (any, error) into the
+// Injector shape, following the same list-directive convention as
+// _scalarValidatorArgs/_scalarValidatorRewrite: when the directive is the
+// first element of an array, the remaining elements become fn's args and
+// the array is collapsed to fn's result in place.
+func wrapPluginFunc(name string, fn func(args ...any) (any, error)) Injector {
+	return func(state *Injection, val any, current any, ref *string, store any) any {
+		var args []any
+		rewrite := false
+
+		if IsList(state.Parent) && state.KeyI == 0 {
+			if parentSlice, ok := state.Parent.([]any); ok {
+				args = append(args, parentSlice[1:]...)
+			}
+			state.KeyI = len(state.Keys)
+			rewrite = true
+		}
+
+		out, err := fn(args...)
+		if err != nil {
+			state.addErr(name + ": " + err.Error())
+		}
+
+		if rewrite {
+			_scalarValidatorRewrite(state, out)
+		}
+
+		return out
+	}
+}
+
+// Extend copies every transform and validator registered on other into r,
+// subject to the same built-in/redefinition checks as a direct Register*
+// call, so composing two registries is exactly as safe as building one by
+// hand. Returns the first error encountered, if any; r is left with
+// whichever entries were copied before that point.
+func (r *Registry) Extend(other *Registry) error {
+	if other == nil {
+		return nil
+	}
+	for name, fn := range other.transforms {
+		if err := r.RegisterTransform(name, fn); err != nil {
+			return err
+		}
+	}
+	for name, fn := range other.validators {
+		if err := r.RegisterValidator(name, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TransformWith is Transform with reg's registered transforms and
+// validators made available inside spec, alongside the package's own
+// built-ins - the plugin counterpart to TransformModify's extra store
+// parameter. A nil reg behaves exactly like Transform.
+func TransformWith(data any, spec any, reg *Registry) any {
+	extra := map[string]any{}
+	if reg != nil {
+		for name, fn := range reg.transforms {
+			extra[name] = fn
+		}
+		for name, fn := range reg.validators {
+			extra[name] = fn
+		}
+	}
+	return TransformModify(data, spec, extra, nil)
+}
@@ -0,0 +1,142 @@
+package voxgigstruct_test
+
+import (
+	"fmt"
+	"testing"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+func TestRegistryRegisterTransformRejectsNameWithoutDollarPrefix(t *testing.T) {
+	reg := voxgigstruct.NewRegistry()
+	err := reg.RegisterTransform("UUID", func(*voxgigstruct.Injection, any, any, *string, any) any { return nil })
+	if err == nil {
+		t.Fatalf("expected an error for a name not beginning with \"$\"")
+	}
+}
+
+func TestRegistryRegisterTransformRejectsBuiltinRedefinition(t *testing.T) {
+	reg := voxgigstruct.NewRegistry()
+	err := reg.RegisterTransform("$COPY", func(*voxgigstruct.Injection, any, any, *string, any) any { return nil })
+	if err == nil {
+		t.Fatalf("expected an error when redefining the built-in $COPY without AllowOverride")
+	}
+}
+
+func TestRegistryAllowOverridePermitsBuiltinRedefinition(t *testing.T) {
+	reg := voxgigstruct.NewRegistry()
+	reg.AllowOverride = true
+	err := reg.RegisterTransform("$COPY", func(*voxgigstruct.Injection, any, any, *string, any) any { return "overridden" })
+	if err != nil {
+		t.Fatalf("expected AllowOverride to permit redefining $COPY, got %v", err)
+	}
+}
+
+func TestRegistryRegisterTransformRejectsDuplicateName(t *testing.T) {
+	reg := voxgigstruct.NewRegistry()
+	first := func(*voxgigstruct.Injection, any, any, *string, any) any { return nil }
+	if err := reg.RegisterTransform("$UUID", first); err != nil {
+		t.Fatalf("expected first registration to succeed, got %v", err)
+	}
+	if err := reg.RegisterTransform("$UUID", first); err == nil {
+		t.Fatalf("expected a duplicate registration of $UUID to fail without AllowOverride")
+	}
+}
+
+func TestRegistryRegisterFunctionRejectsWrongSignature(t *testing.T) {
+	reg := voxgigstruct.NewRegistry()
+	err := reg.RegisterFunction("$BAD", func(int) int { return 0 })
+	if err == nil {
+		t.Fatalf("expected RegisterFunction to reject a fn that isn't func(args ...any) (any, error)")
+	}
+}
+
+func TestRegistryRegisterFunctionBareUsage(t *testing.T) {
+	reg := voxgigstruct.NewRegistry()
+	err := reg.RegisterFunction("$GREETING", func(args ...any) (any, error) {
+		return "hello", nil
+	})
+	if err != nil {
+		t.Fatalf("expected RegisterFunction to succeed, got %v", err)
+	}
+
+	spec := map[string]any{"msg": "`$GREETING`"}
+	out := voxgigstruct.TransformWith(map[string]any{}, spec, reg)
+
+	outMap, ok := out.(map[string]any)
+	if !ok || outMap["msg"] != "hello" {
+		t.Fatalf("expected msg to be set to \"hello\" by the registered function, got %+v", out)
+	}
+}
+
+func TestRegistryRegisterFunctionListDirectiveUsage(t *testing.T) {
+	reg := voxgigstruct.NewRegistry()
+	err := reg.RegisterFunction("$CONCAT", func(args ...any) (any, error) {
+		out := ""
+		for _, a := range args {
+			s, _ := a.(string)
+			out += s
+		}
+		return out, nil
+	})
+	if err != nil {
+		t.Fatalf("expected RegisterFunction to succeed, got %v", err)
+	}
+
+	spec := map[string]any{"full": []any{"`$CONCAT`", "foo", "bar"}}
+	out := voxgigstruct.TransformWith(map[string]any{}, spec, reg)
+
+	outMap, ok := out.(map[string]any)
+	if !ok || outMap["full"] != "foobar" {
+		t.Fatalf("expected full to be set to \"foobar\" from the directive's list args, got %+v", out)
+	}
+}
+
+func TestRegistryExtendComposesTwoRegistries(t *testing.T) {
+	a := voxgigstruct.NewRegistry()
+	if err := a.RegisterFunction("$FOO", func(args ...any) (any, error) { return "foo", nil }); err != nil {
+		t.Fatalf("unexpected error registering $FOO: %v", err)
+	}
+
+	b := voxgigstruct.NewRegistry()
+	if err := b.RegisterFunction("$BAR", func(args ...any) (any, error) { return "bar", nil }); err != nil {
+		t.Fatalf("unexpected error registering $BAR: %v", err)
+	}
+
+	if err := a.Extend(b); err != nil {
+		t.Fatalf("expected Extend to compose non-overlapping registries, got %v", err)
+	}
+
+	spec := map[string]any{"x": "`$FOO`", "y": "`$BAR`"}
+	out := voxgigstruct.TransformWith(map[string]any{}, spec, a)
+
+	outMap, ok := out.(map[string]any)
+	if !ok || outMap["x"] != "foo" || outMap["y"] != "bar" {
+		t.Fatalf("expected both $FOO and $BAR to be available after Extend, got %+v", out)
+	}
+}
+
+func TestRegistryExtendRejectsCollision(t *testing.T) {
+	a := voxgigstruct.NewRegistry()
+	if err := a.RegisterFunction("$FOO", func(args ...any) (any, error) { return "a", nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	b := voxgigstruct.NewRegistry()
+	if err := b.RegisterFunction("$FOO", func(args ...any) (any, error) { return "b", nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := a.Extend(b); err == nil {
+		t.Fatalf("expected Extend to reject a colliding $FOO registration without AllowOverride")
+	}
+}
+
+func TestTransformWithNilRegistryBehavesLikeTransform(t *testing.T) {
+	spec := map[string]any{"name": "`$COPY`"}
+	out := voxgigstruct.TransformWith(map[string]any{"name": "Ada"}, spec, nil)
+	outMap, ok := out.(map[string]any)
+	if !ok || outMap["name"] != "Ada" {
+		t.Fatalf("expected a nil Registry to fall back to built-in transforms, got %+v (%v)", out, fmt.Sprintf("%T", out))
+	}
+}
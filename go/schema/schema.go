@@ -0,0 +1,267 @@
+/* Copyright (c) 2025 Voxgig Ltd. MIT LICENSE. */
+
+/* Package schema bridges the struct-jose shorthand validation DSL
+ * (used by voxgigstruct.ValidateCollect, e.g. `` `$STRING` ``,
+ * `` `$ONE` ``, `` `$EXACT` ``) and Draft-2020-12 JSON Schema.
+ *
+ * ToJSONSchema translates a struct-jose shape into a JSON Schema
+ * document, so a struct-jose spec can be handed to any off-the-shelf
+ * JSON Schema validator. FromJSONSchema does the reverse, so existing
+ * OpenAPI/JSON Schema fragments can be ingested as struct-jose shapes.
+ *
+ * The mapping is necessarily lossy in both directions: struct-jose
+ * shapes can express things JSON Schema cannot (e.g. `` `$FUNCTION` ``),
+ * and JSON Schema can express things struct-jose cannot (e.g. numeric
+ * ranges). Only the overlapping, commonly used subset is supported. On
+ * the way out, `` `$FUNCTION` `` and `` `$ANY` `` become the empty schema
+ * {} (anything goes) and any other scalar, including an unrecognised
+ * directive string, becomes a "const"; on the way back, a schema with no
+ * recognised keyword becomes `` `$ANY` ``.
+ */
+package schema
+
+import (
+	"fmt"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+const (
+	dString   = "`$STRING`"
+	dNumber   = "`$NUMBER`"
+	dBoolean  = "`$BOOLEAN`"
+	dObject   = "`$OBJECT`"
+	dArray    = "`$ARRAY`"
+	dFunction = "`$FUNCTION`"
+	dAny      = "`$ANY`"
+	dOne      = "`$ONE`"
+	dExact    = "`$EXACT`"
+	dChild    = "`$CHILD`"
+)
+
+// ToJSONSchema translates a struct-jose shorthand shape into a
+// Draft-2020-12 JSON Schema document.
+func ToJSONSchema(shape any) (map[string]any, error) {
+	return toSchema(shape)
+}
+
+func toSchema(shape any) (map[string]any, error) {
+	switch {
+	case shape == dString:
+		return map[string]any{"type": "string"}, nil
+
+	case shape == dNumber:
+		return map[string]any{"type": "number"}, nil
+
+	case shape == dBoolean:
+		return map[string]any{"type": "boolean"}, nil
+
+	case shape == dObject:
+		return map[string]any{"type": "object"}, nil
+
+	case shape == dArray:
+		return map[string]any{"type": "array"}, nil
+
+	case shape == dFunction:
+		// JSON Schema has no function type; there is nothing to validate
+		// structurally, so accept anything.
+		return map[string]any{}, nil
+
+	case shape == dAny:
+		return map[string]any{}, nil
+	}
+
+	if voxgigstruct.IsList(shape) {
+		list := shape.([]any)
+		if len(list) > 0 {
+			if head, ok := list[0].(string); ok {
+				switch head {
+				case dOne:
+					return toOneOf(list[1:])
+				case dExact:
+					return toConst(list[1:])
+				}
+			}
+		}
+		return toArraySchema(list)
+	}
+
+	if voxgigstruct.IsMap(shape) {
+		m := shape.(map[string]any)
+
+		if child, has := m[dChild]; has {
+			childSchema, err := toSchema(child)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{
+				"type":                 "object",
+				"additionalProperties": childSchema,
+			}, nil
+		}
+
+		return toObjectSchema(m)
+	}
+
+	// A literal scalar default value: treat it as a const.
+	return map[string]any{"const": shape}, nil
+}
+
+func toOneOf(alts []any) (map[string]any, error) {
+	oneOf := make([]any, 0, len(alts))
+	for _, alt := range alts {
+		s, err := toSchema(alt)
+		if err != nil {
+			return nil, err
+		}
+		oneOf = append(oneOf, s)
+	}
+	return map[string]any{"oneOf": oneOf}, nil
+}
+
+func toConst(vals []any) (map[string]any, error) {
+	if len(vals) == 1 {
+		return map[string]any{"const": vals[0]}, nil
+	}
+
+	enum := make([]any, len(vals))
+	copy(enum, vals)
+	return map[string]any{"enum": enum}, nil
+}
+
+func toArraySchema(list []any) (map[string]any, error) {
+	if len(list) == 0 {
+		return map[string]any{"type": "array"}, nil
+	}
+
+	items, err := toSchema(list[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]any{
+		"type":  "array",
+		"items": items,
+	}, nil
+}
+
+func toObjectSchema(m map[string]any) (map[string]any, error) {
+	properties := make(map[string]any, len(m))
+	required := make([]any, 0, len(m))
+
+	for _, kv := range voxgigstruct.Items(m) {
+		key, _ := kv[0].(string)
+		propSchema, err := toSchema(kv[1])
+		if err != nil {
+			return nil, fmt.Errorf("schema: property %q: %w", key, err)
+		}
+		properties[key] = propSchema
+		required = append(required, key)
+	}
+
+	out := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		out["required"] = required
+	}
+
+	return out, nil
+}
+
+// FromJSONSchema translates a Draft-2020-12 JSON Schema document into a
+// struct-jose shorthand shape.
+func FromJSONSchema(jsonschema map[string]any) (any, error) {
+	return fromSchema(jsonschema)
+}
+
+func fromSchema(s any) (any, error) {
+	m, ok := s.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("schema: expected a JSON Schema object, got %T", s)
+	}
+
+	if constVal, has := m["const"]; has {
+		return []any{dExact, constVal}, nil
+	}
+
+	if enumVal, has := m["enum"]; has {
+		if list, ok := enumVal.([]any); ok {
+			return append([]any{dExact}, list...), nil
+		}
+	}
+
+	if oneOf, has := m["oneOf"]; has {
+		return fromAlternatives(oneOf, dOne)
+	}
+	if anyOf, has := m["anyOf"]; has {
+		return fromAlternatives(anyOf, dOne)
+	}
+
+	if addl, has := m["additionalProperties"]; has {
+		if addlSchema, ok := addl.(map[string]any); ok {
+			childShape, err := fromSchema(addlSchema)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{dChild: childShape}, nil
+		}
+	}
+
+	switch m["type"] {
+	case "string":
+		return dString, nil
+	case "number", "integer":
+		return dNumber, nil
+	case "boolean":
+		return dBoolean, nil
+	case "array":
+		if items, has := m["items"]; has {
+			itemShape, err := fromSchema(items)
+			if err != nil {
+				return nil, err
+			}
+			return []any{itemShape}, nil
+		}
+		return dArray, nil
+	case "object":
+		return fromObjectSchema(m)
+	}
+
+	return dAny, nil
+}
+
+func fromAlternatives(alts any, head string) (any, error) {
+	list, ok := alts.([]any)
+	if !ok {
+		return nil, fmt.Errorf("schema: expected an array of alternatives")
+	}
+
+	out := []any{head}
+	for _, alt := range list {
+		shape, err := fromSchema(alt)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, shape)
+	}
+	return out, nil
+}
+
+func fromObjectSchema(m map[string]any) (any, error) {
+	properties, _ := m["properties"].(map[string]any)
+
+	out := make(map[string]any, len(properties))
+	for _, kv := range voxgigstruct.Items(properties) {
+		key, _ := kv[0].(string)
+		shape, err := fromSchema(kv[1])
+		if err != nil {
+			return nil, fmt.Errorf("schema: property %q: %w", key, err)
+		}
+		out[key] = shape
+	}
+
+	return out, nil
+}
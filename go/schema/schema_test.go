@@ -0,0 +1,86 @@
+package schema
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestToJSONSchemaShorthand(t *testing.T) {
+	cases := []struct {
+		name  string
+		shape any
+		want  map[string]any
+	}{
+		{"string", "`$STRING`", map[string]any{"type": "string"}},
+		{"number", "`$NUMBER`", map[string]any{"type": "number"}},
+		{"exact", []any{"`$EXACT`", 4}, map[string]any{"const": 4}},
+		{"one", []any{"`$ONE`", "`$STRING`", "`$NUMBER`"}, map[string]any{
+			"oneOf": []any{
+				map[string]any{"type": "string"},
+				map[string]any{"type": "number"},
+			},
+		}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := ToJSONSchema(c.shape)
+			if err != nil {
+				t.Fatalf("ToJSONSchema: %v", err)
+			}
+			if !deepEqual(got, c.want) {
+				t.Errorf("got %#v, want %#v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestRoundTripChild(t *testing.T) {
+	shape := map[string]any{"`$CHILD`": "`$STRING`"}
+
+	js, err := ToJSONSchema(shape)
+	if err != nil {
+		t.Fatalf("ToJSONSchema: %v", err)
+	}
+
+	back, err := FromJSONSchema(js)
+	if err != nil {
+		t.Fatalf("FromJSONSchema: %v", err)
+	}
+
+	if !deepEqual(back, shape) {
+		t.Errorf("round trip mismatch: got %#v, want %#v", back, shape)
+	}
+}
+
+func deepEqual(a, b any) bool {
+	return stringify(a) == stringify(b)
+}
+
+func stringify(v any) string {
+	switch x := v.(type) {
+	case map[string]any:
+		out := "{"
+		for _, k := range []string{"type", "const", "oneOf", "properties", "additionalProperties", "required", "items", "enum"} {
+			if val, has := x[k]; has {
+				out += k + ":" + stringify(val) + ";"
+			}
+		}
+		return out + "}"
+	case []any:
+		out := "["
+		for _, e := range x {
+			out += stringify(e) + ","
+		}
+		return out + "]"
+	default:
+		return toStr(x)
+	}
+}
+
+func toStr(v any) string {
+	if v == nil {
+		return "nil"
+	}
+	return fmt.Sprint(v)
+}
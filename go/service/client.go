@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc"
+)
+
+// Client is a thin wrapper around a gRPC connection to the Struct
+// service, exposing the same call shapes as the in-process SDK.
+type Client struct {
+	conn *grpc.ClientConn
+}
+
+// Dial connects to a Struct service at addr.
+func Dial(addr string, opts ...grpc.DialOption) (*Client, error) {
+	opts = append(opts, grpc.WithDefaultCallOptions(grpc.CallContentSubtype(codecName)))
+	conn, err := grpc.NewClient(addr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn}, nil
+}
+
+// Close releases the underlying gRPC connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) call(ctx context.Context, method string, req, resp any) error {
+	fullMethod := "/" + serviceName + "/" + method
+	return c.conn.Invoke(ctx, fullMethod, req, resp)
+}
+
+// Validate calls the remote Validate RPC.
+func (c *Client) Validate(ctx context.Context, data, spec any) (any, error) {
+	resp := map[string]any{}
+	err := c.call(ctx, "Validate", map[string]any{"data": data, "spec": spec}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp["out"], nil
+}
+
+// Inject calls the remote Inject RPC.
+func (c *Client) Inject(ctx context.Context, val, store any) (any, error) {
+	resp := map[string]any{}
+	err := c.call(ctx, "Inject", map[string]any{"val": val, "store": store}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp["out"], nil
+}
+
+// GetPath calls the remote GetPath RPC.
+func (c *Client) GetPath(ctx context.Context, path []string, store any) (any, error) {
+	resp := map[string]any{}
+	err := c.call(ctx, "GetPath", map[string]any{"path": path, "store": store}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp["out"], nil
+}
+
+// Walk calls the remote Walk RPC.
+func (c *Client) Walk(ctx context.Context, val any) (any, error) {
+	resp := map[string]any{}
+	err := c.call(ctx, "Walk", map[string]any{"val": val}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return resp["out"], nil
+}
+
+// Contextify calls the remote Contextify RPC.
+func (c *Client) Contextify(ctx context.Context, cctx map[string]any) (map[string]any, error) {
+	resp := map[string]any{}
+	err := c.call(ctx, "Contextify", map[string]any{"ctx": cctx}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	out, _ := resp["ctx"].(map[string]any)
+	return out, nil
+}
+
+// Check calls the remote Check RPC.
+func (c *Client) Check(ctx context.Context, cctx map[string]any) (map[string]any, error) {
+	resp := map[string]any{}
+	err := c.call(ctx, "Check", map[string]any{"ctx": cctx}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	out, _ := resp["out"].(map[string]any)
+	return out, nil
+}
+
+// Invoke calls the remote Invoke RPC, which dispatches method (capitalized,
+// e.g. "check" -> "Check") against the server's Utility via reflection,
+// passing args positionally. This is what lets a caller drive an arbitrary
+// Utility method by name over the wire, rather than requiring a dedicated
+// RPC (like Check above) per method.
+func (c *Client) Invoke(ctx context.Context, method string, args []any) (any, error) {
+	resp := map[string]any{}
+	err := c.call(ctx, "Invoke", map[string]any{"method": method, "args": args}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	if errMsg, ok := resp["error"].(string); ok && errMsg != "" {
+		return resp["out"], errors.New(errMsg)
+	}
+	return resp["out"], nil
+}
+
+// WatchResult is one item streamed back by Watch.
+type WatchResult struct {
+	Out any
+	Err error
+}
+
+// Watch calls the remote streaming Watch RPC, invoking method count times
+// (each a fresh callMethod dispatch server-side) and streaming one
+// WatchResult per invocation on the returned channel. The channel is closed
+// once the server has sent count results or the stream ends.
+func (c *Client) Watch(ctx context.Context, method string, args []any, count int) (<-chan WatchResult, error) {
+	desc := &grpc.StreamDesc{StreamName: "Watch", ServerStreams: true}
+	stream, err := c.conn.NewStream(ctx, desc, "/"+serviceName+"/Watch")
+	if err != nil {
+		return nil, err
+	}
+
+	req := map[string]any{"method": method, "args": args, "count": count}
+	if err := stream.SendMsg(req); err != nil {
+		return nil, err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	results := make(chan WatchResult)
+	go func() {
+		defer close(results)
+		for {
+			resp := map[string]any{}
+			if err := stream.RecvMsg(&resp); err != nil {
+				if err != io.EOF {
+					results <- WatchResult{Err: err}
+				}
+				return
+			}
+			var rerr error
+			if errMsg, ok := resp["error"].(string); ok && errMsg != "" {
+				rerr = errors.New(errMsg)
+			}
+			results <- WatchResult{Out: resp["out"], Err: rerr}
+		}
+	}()
+	return results, nil
+}
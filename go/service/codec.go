@@ -0,0 +1,31 @@
+package service
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc/encoding"
+)
+
+const codecName = "voxgig-json"
+
+// jsonCodec lets the Struct service exchange its messages as plain JSON
+// over gRPC, rather than requiring protobuf-generated message types. This
+// keeps the wire messages (structv1) simple map[string]any/any trees that
+// match what the rest of voxgigstruct already assumes.
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+func (jsonCodec) Name() string {
+	return codecName
+}
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
@@ -0,0 +1,65 @@
+package service_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/voxgig/struct/service"
+	runner "github.com/voxgig/struct/testutil"
+)
+
+// TestCheckParity proves that SDKUtility.Check behaves identically whether
+// called in-process or over the gRPC transport, the same way client.Check
+// is exercised directly against the SDK in client_test.go.
+func TestCheckParity(t *testing.T) {
+	sdk, err := runner.TestSDK(map[string]any{"foo": 1})
+	if err != nil {
+		t.Fatalf("TestSDK: %v", err)
+	}
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	service.RegisterStructServer(srv, sdk.Utility())
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultCallOptions(grpc.CallContentSubtype("voxgig-json")),
+	)
+	if err != nil {
+		t.Fatalf("grpc.NewClient: %v", err)
+	}
+	defer conn.Close()
+
+	cases := []map[string]any{
+		nil,
+		{"meta": map[string]any{"bar": "x"}},
+		{"meta": map[string]any{}},
+	}
+
+	for _, cctx := range cases {
+		direct := sdk.Utility().Check(cctx)
+
+		remote := map[string]any{}
+		err := conn.Invoke(context.Background(), "/struct.v1.Struct/Check",
+			map[string]any{"ctx": cctx}, &remote)
+		if err != nil {
+			t.Fatalf("Check RPC: %v", err)
+		}
+		out, _ := remote["out"].(map[string]any)
+
+		if direct["zed"] != out["zed"] {
+			t.Errorf("parity mismatch: direct=%v remote=%v", direct, out)
+		}
+	}
+}
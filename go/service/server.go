@@ -0,0 +1,258 @@
+/* Copyright (c) 2025 Voxgig Ltd. MIT LICENSE. */
+
+// Package service exposes voxgigstruct's SDK/Utility surface over gRPC, so
+// non-Go clients can drive validation, injection, and path lookups against
+// the same struct-jose semantics Go callers get in-process.
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+
+	"google.golang.org/grpc"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+const serviceName = "struct.v1.Struct"
+
+// Serve starts a gRPC server on addr, wrapping utility (an SDK's
+// Utility() value) for remote callers. It blocks until the listener
+// errors or the server is stopped. Takes the Utility value directly,
+// rather than the SDK it came from, so this package never needs to name
+// testutil/runner's SDK/Utility types - testutil's gRPC-backed test
+// Subject (NewGRPCSubject) imports this package to dial the service, and
+// the two would otherwise form an import cycle.
+func Serve(addr string, utility any) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	srv := grpc.NewServer()
+	RegisterStructServer(srv, utility)
+
+	return srv.Serve(lis)
+}
+
+// RegisterStructServer registers the Struct service on an existing gRPC
+// server, for callers that want to host it alongside other services.
+func RegisterStructServer(srv *grpc.Server, utility any) {
+	srv.RegisterService(&serviceDesc, &structServer{utility: utility})
+}
+
+type structServer struct {
+	utility any
+}
+
+func (s *structServer) validate(ctx context.Context, req map[string]any) (any, error) {
+	data := req["data"]
+	spec := req["spec"]
+
+	out, err := voxgigstruct.Validate(data, spec)
+
+	resp := map[string]any{"out": out}
+	if err != nil {
+		resp["error"] = err.Error()
+	}
+	return resp, nil
+}
+
+func (s *structServer) inject(ctx context.Context, req map[string]any) (any, error) {
+	val := req["val"]
+	store := req["store"]
+	return map[string]any{"out": voxgigstruct.Inject(val, store)}, nil
+}
+
+func (s *structServer) getPath(ctx context.Context, req map[string]any) (any, error) {
+	path := req["path"]
+	store := req["store"]
+	return map[string]any{"out": voxgigstruct.GetPath(path, store)}, nil
+}
+
+func (s *structServer) walk(ctx context.Context, req map[string]any) (any, error) {
+	val := req["val"]
+	out := voxgigstruct.Walk(val, func(key *string, val any, parent any, path []string) any {
+		return val
+	})
+	return map[string]any{"out": out}, nil
+}
+
+func (s *structServer) contextify(ctx context.Context, req map[string]any) (any, error) {
+	cctx, _ := req["ctx"].(map[string]any)
+	out := s.utility.(interface {
+		Contextify(map[string]any) map[string]any
+	}).Contextify(cctx)
+	return map[string]any{"ctx": out}, nil
+}
+
+func (s *structServer) check(ctx context.Context, req map[string]any) (any, error) {
+	cctx, _ := req["ctx"].(map[string]any)
+	checker, ok := s.utility.(interface {
+		Check(map[string]any) map[string]any
+	})
+	if !ok {
+		return nil, fmt.Errorf("service: utility does not implement Check")
+	}
+	return map[string]any{"out": checker.Check(cctx)}, nil
+}
+
+// invoke dispatches req["method"] (capitalized, e.g. "check" -> "Check")
+// against s.utility via reflection, passing req["args"] positionally -
+// the same uppercase-first-letter convention
+// testutil/runner.resolveSubject uses to turn a lowercase RunSpec name
+// into an exported Go method name. This is what lets
+// testutil.NewGRPCSubject drive an arbitrary Utility method by name,
+// rather than requiring a dedicated RPC (like Check above) per method.
+func (s *structServer) invoke(ctx context.Context, req map[string]any) (any, error) {
+	method, _ := req["method"].(string)
+	args, _ := req["args"].([]any)
+
+	out, err := callMethod(s.utility, method, args)
+	resp := map[string]any{"out": out}
+	if err != nil {
+		resp["error"] = err.Error()
+	}
+	return resp, nil
+}
+
+// callMethod invokes the exported method named (capitalized) on target via
+// reflection, passing args positionally.
+func callMethod(target any, name string, args []any) (any, error) {
+	if name == "" {
+		return nil, fmt.Errorf("service: empty method name")
+	}
+
+	methodName := strings.ToUpper(name[:1]) + name[1:]
+	val := reflect.ValueOf(target).MethodByName(methodName)
+	if !val.IsValid() {
+		return nil, fmt.Errorf("service: %s has no method %q", reflect.TypeOf(target), methodName)
+	}
+
+	fnType := val.Type()
+	in := make([]reflect.Value, fnType.NumIn())
+	for i := 0; i < fnType.NumIn(); i++ {
+		paramType := fnType.In(i)
+		if i >= len(args) || args[i] == nil {
+			in[i] = reflect.Zero(paramType)
+			continue
+		}
+		arg := reflect.ValueOf(args[i])
+		if !arg.Type().AssignableTo(paramType) {
+			return nil, fmt.Errorf("service: argument %d type %T not assignable to parameter type %s", i, args[i], paramType)
+		}
+		in[i] = arg
+	}
+
+	out := val.Call(in)
+	switch len(out) {
+	case 0:
+		return nil, nil
+	case 1:
+		if err, ok := out[0].Interface().(error); ok {
+			return nil, err
+		}
+		return out[0].Interface(), nil
+	default:
+		var err error
+		if e, ok := out[len(out)-1].Interface().(error); ok {
+			err = e
+		}
+		return out[0].Interface(), err
+	}
+}
+
+// watchHandler implements the streaming Watch RPC: it reads a single
+// {"method", "args", "count"} request, then invokes that method via
+// callMethod count times (default 1), streaming one response per
+// invocation. The SDK surface has no genuine push-based change feed
+// today, so this is a minimal, honest poll-N-times scaffold rather than a
+// real watch; it exists so NewGRPCSubject/MakeRunnerWithTransport have a
+// streaming RPC to drive if a future SDK method benefits from one.
+func watchHandler(srv any, stream grpc.ServerStream) error {
+	s := srv.(*structServer)
+
+	req := map[string]any{}
+	if err := stream.RecvMsg(&req); err != nil {
+		return err
+	}
+
+	method, _ := req["method"].(string)
+	args, _ := req["args"].([]any)
+	count := 1
+	if c, ok := req["count"].(float64); ok && c > 0 {
+		count = int(c)
+	}
+
+	for i := 0; i < count; i++ {
+		out, err := callMethod(s.utility, method, args)
+		resp := map[string]any{"out": out}
+		if err != nil {
+			resp["error"] = err.Error()
+		}
+		if err := stream.SendMsg(resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// serviceDesc is the gRPC service descriptor for the Struct service,
+// defined by hand (rather than protoc-generated) since the wire messages
+// are plain JSON (see codec.go).
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{
+		unaryMethod("Validate", func(s *structServer) handlerFunc { return s.validate }),
+		unaryMethod("Inject", func(s *structServer) handlerFunc { return s.inject }),
+		unaryMethod("GetPath", func(s *structServer) handlerFunc { return s.getPath }),
+		unaryMethod("Walk", func(s *structServer) handlerFunc { return s.walk }),
+		unaryMethod("Contextify", func(s *structServer) handlerFunc { return s.contextify }),
+		unaryMethod("Check", func(s *structServer) handlerFunc { return s.check }),
+		unaryMethod("Invoke", func(s *structServer) handlerFunc { return s.invoke }),
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       watchHandler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "apis/proto/struct/v1/struct.proto",
+}
+
+type handlerFunc func(ctx context.Context, req map[string]any) (any, error)
+
+func unaryMethod(name string, bind func(*structServer) handlerFunc) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: name,
+		Handler: func(
+			srv any,
+			ctx context.Context,
+			dec func(any) error,
+			interceptor grpc.UnaryServerInterceptor,
+		) (any, error) {
+			s := srv.(*structServer)
+			fn := bind(s)
+
+			req := map[string]any{}
+			if err := dec(&req); err != nil {
+				return nil, err
+			}
+
+			if interceptor == nil {
+				return fn(ctx, req)
+			}
+
+			info := &grpc.UnaryServerInfo{Server: srv, FullMethod: serviceName + "/" + name}
+			handler := func(ctx context.Context, req any) (any, error) {
+				return fn(ctx, req.(map[string]any))
+			}
+			return interceptor(ctx, req, info, handler)
+		},
+	}
+}
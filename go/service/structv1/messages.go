@@ -0,0 +1,61 @@
+// Package structv1 holds the wire messages for the Struct gRPC service
+// declared in apis/proto/struct/v1/struct.proto. Fields mirror the proto
+// messages field-for-field; the Invoke and Watch messages have no type
+// here (the server and client exchange those as plain maps).
+// google.protobuf.Struct/Value payloads are
+// represented here as plain map[string]any / any so the voxgigstruct
+// codec (see service.codec) can encode them directly, without forcing
+// callers to depend on a generated structpb tree.
+package structv1
+
+type ValidateRequest struct {
+	Data any `json:"data"`
+	Spec any `json:"spec"`
+}
+
+type ValidateResponse struct {
+	Out   any    `json:"out"`
+	Error string `json:"error,omitempty"`
+}
+
+type InjectRequest struct {
+	Val   any `json:"val"`
+	Store any `json:"store"`
+}
+
+type InjectResponse struct {
+	Out any `json:"out"`
+}
+
+type GetPathRequest struct {
+	Path  []string `json:"path"`
+	Store any      `json:"store"`
+}
+
+type GetPathResponse struct {
+	Out any `json:"out"`
+}
+
+type WalkRequest struct {
+	Val any `json:"val"`
+}
+
+type WalkResponse struct {
+	Out any `json:"out"`
+}
+
+type ContextifyRequest struct {
+	Ctx map[string]any `json:"ctx"`
+}
+
+type ContextifyResponse struct {
+	Ctx map[string]any `json:"ctx"`
+}
+
+type CheckRequest struct {
+	Ctx map[string]any `json:"ctx"`
+}
+
+type CheckResponse struct {
+	Out map[string]any `json:"out"`
+}
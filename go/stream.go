@@ -0,0 +1,225 @@
+package voxgigstruct
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// StreamAction tells TransformStream what to do with an element whose
+// transform produced an error, as reported to OnError.
+type StreamAction int
+
+const (
+	// StreamContinue keeps the pipeline running, writing the element's
+	// original (untransformed) value in place of the failed result.
+	StreamContinue StreamAction = iota
+
+	// StreamSkip keeps the pipeline running, omitting the element from
+	// the output entirely.
+	StreamSkip
+
+	// StreamAbort stops TransformStream, which returns err to the caller.
+	StreamAbort
+)
+
+// StreamOptions configures TransformStream.
+type StreamOptions struct {
+	// Concurrency is the number of worker goroutines transforming
+	// elements in parallel. Defaults to 1 if not positive.
+	Concurrency int
+
+	// BufferSize is the capacity of the internal work and result
+	// channels. Defaults to Concurrency if not positive.
+	BufferSize int
+
+	// OnError is called for each element whose transform collected
+	// errors (via the same $ERRS mechanism ValidateCollect/
+	// TransformTainted use), and decides how TransformStream proceeds.
+	// A nil OnError behaves as if it always returned StreamContinue.
+	OnError func(idx int, err error) StreamAction
+}
+
+// streamResult is one worker's output for one array element, carried
+// through the result channel and reassembled into index order.
+type streamResult struct {
+	idx int
+	val any
+	raw any
+	err error
+}
+
+// TransformStream reads a top-level JSON array from dec one element at a
+// time, running spec's transform pipeline against each element (bound to
+// $TOP, exactly like Transform) on a pool of worker goroutines, and
+// writes each result to out in the original element order - so $EACH/
+// $PACK inside spec see the same per-element $TOP they would under
+// Transform, however the elements arrive.
+//
+// Each element is transformed with its own freshly-built store (the same
+// per-call store TransformModify always builds) seeded with its own
+// $ERRS collector, so errors from one element can never leak into
+// another's. Order is preserved across concurrent workers with a small
+// reorder buffer keyed by element index; results are written to out as
+// soon as they become the next in sequence, so memory use stays bounded
+// by BufferSize rather than by the size of the input array.
+func TransformStream(dec *json.Decoder, spec any, out *json.Encoder, opts StreamOptions) error {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	bufferSize := opts.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = concurrency
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("TransformStream: expected input to start with a JSON array")
+	}
+
+	type work struct {
+		idx int
+		val any
+	}
+
+	workCh := make(chan work, bufferSize)
+	resultCh := make(chan streamResult, bufferSize)
+	cancel := make(chan struct{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for w := range workCh {
+				resultCh <- transformStreamElement(w.idx, w.val, spec)
+			}
+		}()
+	}
+
+	var readErr error
+	go func() {
+		defer close(workCh)
+		idx := 0
+		for dec.More() {
+			var elem any
+			if decErr := dec.Decode(&elem); decErr != nil {
+				readErr = decErr
+				return
+			}
+			select {
+			case workCh <- work{idx: idx, val: elem}:
+				idx++
+			case <-cancel:
+				return
+			}
+		}
+		if _, tokErr := dec.Token(); tokErr != nil {
+			readErr = tokErr
+		}
+	}()
+
+	go func() {
+		wg.Wait()
+		close(resultCh)
+	}()
+
+	pending := map[int]streamResult{}
+	next := 0
+	var outErr error
+
+	for res := range resultCh {
+		pending[res.idx] = res
+
+		for {
+			r, has := pending[next]
+			if !has {
+				break
+			}
+			delete(pending, next)
+			next++
+
+			if r.err != nil {
+				action := StreamContinue
+				if opts.OnError != nil {
+					action = opts.OnError(r.idx, r.err)
+				}
+
+				switch action {
+				case StreamAbort:
+					outErr = r.err
+				case StreamSkip:
+					continue
+				case StreamContinue:
+					r.val = r.raw
+				}
+
+				if outErr != nil {
+					break
+				}
+			}
+
+			if encErr := out.Encode(r.val); encErr != nil {
+				outErr = encErr
+				break
+			}
+		}
+
+		if outErr != nil {
+			close(cancel)
+			break
+		}
+	}
+
+	// Drain whatever workers are still in flight so they can exit after
+	// an early abort, rather than blocking forever on a channel send
+	// nobody is reading from anymore.
+	if outErr != nil {
+		for range resultCh {
+		}
+	}
+
+	wg.Wait()
+
+	if outErr != nil {
+		return outErr
+	}
+	if readErr != nil {
+		return readErr
+	}
+
+	return nil
+}
+
+// transformStreamElement runs spec's transform pipeline against elem as
+// a standalone TransformModify call, so elem is bound to $TOP exactly as
+// Transform would bind it, and collects any errors raised during the
+// transform (e.g. by a validator reused inside the spec) into a single
+// error scoped to this element alone.
+func transformStreamElement(idx int, elem any, spec any) (res streamResult) {
+	res = streamResult{idx: idx, raw: elem}
+
+	defer func() {
+		if p := recover(); p != nil {
+			res.err = fmt.Errorf("element %d: panic during transform: %v", idx, p)
+		}
+	}()
+
+	errs := ListRefCreate[any]()
+	res.val = TransformModify(elem, spec, map[string]any{"$ERRS": errs}, nil)
+
+	if len(errs.List) > 0 {
+		msgs := make([]string, len(errs.List))
+		for i, e := range errs.List {
+			msgs[i] = fmt.Sprint(e)
+		}
+		res.err = fmt.Errorf("element %d: %s", idx, strings.Join(msgs, "; "))
+	}
+
+	return res
+}
@@ -0,0 +1,132 @@
+package voxgigstruct_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		var v map[string]any
+		if err := dec.Decode(&v); err != nil {
+			t.Fatalf("failed to decode output line: %v", err)
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+func TestTransformStreamPreservesOrderWithConcurrency(t *testing.T) {
+	input := bytes.NewBufferString(`[
+		{"id": 0, "name": "Ada"},
+		{"id": 1, "name": "Bea"},
+		{"id": 2, "name": "Cel"},
+		{"id": 3, "name": "Deb"},
+		{"id": 4, "name": "Eva"}
+	]`)
+	spec := map[string]any{"id": "`$COPY`", "name": "`$COPY`"}
+
+	var output bytes.Buffer
+	err := voxgigstruct.TransformStream(
+		json.NewDecoder(input),
+		spec,
+		json.NewEncoder(&output),
+		voxgigstruct.StreamOptions{Concurrency: 4, BufferSize: 2},
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	results := decodeLines(t, &output)
+	if len(results) != 5 {
+		t.Fatalf("expected 5 results, got %d", len(results))
+	}
+	for i, r := range results {
+		if id, _ := r["id"].(float64); int(id) != i {
+			t.Fatalf("expected output order to match input order, got %+v", results)
+		}
+	}
+}
+
+func TestTransformStreamSkipOmitsFailedElements(t *testing.T) {
+	input := bytes.NewBufferString(`[
+		{"name": "Ada"},
+		{"name": "Bea"},
+		{"name": "Cel"}
+	]`)
+	// An invalid expression fails to parse for every element, the same
+	// deterministic failure TestInjectExprInvalidReturnsNil relies on.
+	spec := map[string]any{"note": "`= 1 +`"}
+
+	var output bytes.Buffer
+	err := voxgigstruct.TransformStream(
+		json.NewDecoder(input),
+		spec,
+		json.NewEncoder(&output),
+		voxgigstruct.StreamOptions{
+			Concurrency: 2,
+			OnError: func(idx int, err error) voxgigstruct.StreamAction {
+				return voxgigstruct.StreamSkip
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	results := decodeLines(t, &output)
+	if len(results) != 0 {
+		t.Fatalf("expected every failed element to be skipped, got %+v", results)
+	}
+}
+
+func TestTransformStreamAbortStopsEarly(t *testing.T) {
+	input := bytes.NewBufferString(`[
+		{"name": "Ada"},
+		{"name": "Bea"},
+		{"name": "Cel"}
+	]`)
+	spec := map[string]any{"note": "`= 1 +`"}
+
+	var output bytes.Buffer
+	err := voxgigstruct.TransformStream(
+		json.NewDecoder(input),
+		spec,
+		json.NewEncoder(&output),
+		voxgigstruct.StreamOptions{
+			Concurrency: 1,
+			OnError: func(idx int, err error) voxgigstruct.StreamAction {
+				return voxgigstruct.StreamAbort
+			},
+		},
+	)
+	if err == nil {
+		t.Fatalf("expected an error from the aborted element")
+	}
+	if !strings.Contains(err.Error(), "element 0") {
+		t.Fatalf("expected the error to reference the failing element index, got %v", err)
+	}
+}
+
+func TestTransformStreamRejectsNonArrayInput(t *testing.T) {
+	input := bytes.NewBufferString(`{"not": "an array"}`)
+	spec := map[string]any{}
+
+	var output bytes.Buffer
+	err := voxgigstruct.TransformStream(
+		json.NewDecoder(input),
+		spec,
+		json.NewEncoder(&output),
+		voxgigstruct.StreamOptions{},
+	)
+	if err == nil {
+		t.Fatalf("expected an error for non-array top-level input")
+	}
+}
@@ -0,0 +1,213 @@
+package voxgigstruct
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// structSpecCache caches the shape spec derived from a struct's exported
+// fields, keyed by its reflect.Type, so ValidateStruct/Validate don't
+// re-walk reflect.VisibleFields on every call against the same type.
+var structSpecCache sync.Map
+
+// ValidateStruct validates data against the shape spec derived from
+// specType's exported fields: string fields become `$STRING`, numeric
+// fields `$NUMBER`, bool fields `$BOOLEAN`, nested or anonymously
+// embedded structs recurse into their own shape, slices/arrays become a
+// $CHILD-templated list of their element shape, and pointer fields are
+// treated as optional. specType may be a struct type or a pointer to one
+// (e.g. reflect.TypeOf(User{}) or reflect.TypeOf((*User)(nil))).
+//
+// A `struct:"..."` field tag refines the derived field, following the
+// same comma-separated convention as encoding/json tags: a leading name
+// renames the field, and "omitempty"/"optional" marks it optional,
+// "default=X" supplies a literal default (which flows through
+// validation's own "spec value was a default, copy over data" branch),
+// and "oneof=a|b|c" restricts the field to an exact set of values via
+// `$EXACT`.
+func ValidateStruct(data any, specType reflect.Type) (any, error) {
+	return Validate(data, structSpecFor(specType))
+}
+
+// structSpecFor returns the shape spec derived from t (or the struct t
+// points to), building it once per reflect.Type and caching the result.
+func structSpecFor(t reflect.Type) any {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if cached, ok := structSpecCache.Load(t); ok {
+		return cached
+	}
+
+	spec := buildStructSpec(t)
+	structSpecCache.Store(t, spec)
+	return spec
+}
+
+// structSpecForSpec recognizes spec as a Go struct type, or a zero-valued
+// (or populated) struct instance/pointer, letting Validate accept either
+// in place of a hand-written map/[]any shape.
+func structSpecForSpec(spec any) (any, bool) {
+	if t, ok := spec.(reflect.Type); ok {
+		return structSpecFor(t), true
+	}
+
+	if spec == nil {
+		return nil, false
+	}
+
+	rv := reflect.ValueOf(spec)
+	for rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, false
+	}
+
+	return structSpecFor(rv.Type()), true
+}
+
+// structFieldOpts is the parsed form of a field's `struct:"..."` tag.
+type structFieldOpts struct {
+	name       string
+	optional   bool
+	hasDefault bool
+	defaultRaw string
+	oneof      []string
+}
+
+// parseStructTag reads f's `struct:"..."` tag. An empty or absent tag
+// keeps f.Name and leaves every option at its zero value.
+func parseStructTag(f reflect.StructField) structFieldOpts {
+	opts := structFieldOpts{name: f.Name}
+
+	tag, ok := f.Tag.Lookup("struct")
+	if !ok || tag == S_MT {
+		return opts
+	}
+
+	parts := strings.Split(tag, ",")
+	if parts[0] != S_MT {
+		opts.name = parts[0]
+	}
+
+	for _, part := range parts[1:] {
+		switch {
+		case part == "omitempty" || part == "optional":
+			opts.optional = true
+		case strings.HasPrefix(part, "default="):
+			opts.hasDefault = true
+			opts.defaultRaw = strings.TrimPrefix(part, "default=")
+		case strings.HasPrefix(part, "oneof="):
+			opts.oneof = strings.Split(strings.TrimPrefix(part, "oneof="), "|")
+		}
+	}
+
+	return opts
+}
+
+// buildStructSpec walks t's visible fields (reflect.VisibleFields, so
+// anonymous embedded structs are flattened in exactly as promoted Go
+// field access would see them) into a map[string]any shape spec.
+func buildStructSpec(t reflect.Type) any {
+	shape := map[string]any{}
+
+	for _, f := range reflect.VisibleFields(t) {
+		// The anonymous field itself is only the embedding marker; its
+		// promoted fields already appear as their own entries.
+		if f.Anonymous || !f.IsExported() {
+			continue
+		}
+
+		opts := parseStructTag(f)
+		if opts.name == "-" {
+			continue
+		}
+
+		ft := f.Type
+		// A field with a default is never required - absent data simply
+		// falls back to that default via validation's own defaulting branch.
+		optional := opts.optional || opts.hasDefault
+		for ft.Kind() == reflect.Ptr {
+			optional = true
+			ft = ft.Elem()
+		}
+
+		var value any
+		switch {
+		case opts.hasDefault:
+			value = parseTypedToken(opts.defaultRaw, ft.Kind())
+		case len(opts.oneof) > 0:
+			alts := make([]any, 0, len(opts.oneof)+1)
+			alts = append(alts, "`$EXACT`")
+			for _, tok := range opts.oneof {
+				alts = append(alts, parseTypedToken(tok, ft.Kind()))
+			}
+			value = alts
+		default:
+			value = fieldSpec(ft)
+		}
+
+		key := opts.name
+		if optional {
+			key += "?"
+		}
+		shape[key] = value
+	}
+
+	return shape
+}
+
+// fieldSpec derives the shape spec for a single field's type: a `$STRING`/
+// `$NUMBER`/`$BOOLEAN` type marker for scalars, a recursive shape for a
+// nested struct, or a $CHILD-templated list for a slice/array, cloning
+// its element shape over every entry - the same mechanism the validator
+// already uses for a hand-written "array of shape X" spec.
+func fieldSpec(t reflect.Type) any {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "`$STRING`"
+	case reflect.Bool:
+		return "`$BOOLEAN`"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "`$NUMBER`"
+	case reflect.Slice, reflect.Array:
+		return []any{"`$CHILD`", fieldSpec(t.Elem())}
+	case reflect.Struct:
+		return buildStructSpec(t)
+	case reflect.Map:
+		return "`$OBJECT`"
+	default:
+		return "`$ANY`"
+	}
+}
+
+// parseTypedToken parses a tag-supplied literal (a "default=" or
+// "oneof=" token) into the Go value kind matching the field it applies
+// to, so the generated spec's literal compares equal to the field's own
+// decoded value (e.g. a JSON number decodes to float64, not string).
+// Falls back to the raw token as a string if it doesn't parse as kind.
+func parseTypedToken(tok string, kind reflect.Kind) any {
+	switch kind {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(tok); err == nil {
+			return b
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if f, err := strconv.ParseFloat(tok, 64); err == nil {
+			return f
+		}
+	}
+	return tok
+}
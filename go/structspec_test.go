@@ -0,0 +1,152 @@
+package voxgigstruct_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+type addressSpec struct {
+	City    string `struct:"city"`
+	Country string `struct:"country,omitempty"`
+}
+
+type userSpec struct {
+	addressSpec
+	Name  string       `struct:"name"`
+	Age   int          `struct:"age,default=18"`
+	Email string       `struct:"email,optional"`
+	Role  string       `struct:"role,oneof=admin|member"`
+	Tags  []string     `struct:"tags"`
+	Home  *addressSpec `struct:"home,optional"`
+}
+
+func TestValidateStructAcceptsMatchingShape(t *testing.T) {
+	data := map[string]any{
+		"name": "Ada",
+		"role": "admin",
+		"tags": []any{"x", "y"},
+		"city": "London",
+	}
+
+	out, err := voxgigstruct.ValidateStruct(data, reflect.TypeOf(userSpec{}))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	outMap, ok := out.(map[string]any)
+	if !ok {
+		t.Fatalf("expected a map result, got %T", out)
+	}
+	if outMap["age"] != float64(18) {
+		t.Fatalf("expected the default age 18 to be filled in, got %+v", outMap["age"])
+	}
+}
+
+func TestValidateStructRejectsBadOneof(t *testing.T) {
+	data := map[string]any{
+		"name": "Ada",
+		"role": "superuser",
+		"tags": []any{},
+		"city": "London",
+	}
+
+	_, err := voxgigstruct.ValidateStruct(data, reflect.TypeOf(userSpec{}))
+	if err == nil {
+		t.Fatalf("expected an error for a role outside the oneof set")
+	}
+}
+
+func TestValidateStructRejectsWrongFieldType(t *testing.T) {
+	data := map[string]any{
+		"name": 42,
+		"role": "admin",
+		"tags": []any{},
+		"city": "London",
+	}
+
+	_, err := voxgigstruct.ValidateStruct(data, reflect.TypeOf(userSpec{}))
+	if err == nil {
+		t.Fatalf("expected an error for a numeric name field")
+	}
+	if !strings.Contains(err.Error(), "name") {
+		t.Fatalf("expected the error to mention the offending field, got %v", err)
+	}
+}
+
+func TestValidateStructDescendsEmbeddedAndNestedStructs(t *testing.T) {
+	data := map[string]any{
+		"name": "Ada",
+		"role": "member",
+		"tags": []any{},
+		"city": "London",
+		"home": map[string]any{"city": "Paris"},
+	}
+
+	out, err := voxgigstruct.ValidateStruct(data, reflect.TypeOf(userSpec{}))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	outMap, ok := out.(map[string]any)
+	if !ok {
+		t.Fatalf("expected a map result, got %T", out)
+	}
+	if outMap["city"] != "London" {
+		t.Fatalf("expected the embedded addressSpec's city field to validate, got %+v", outMap["city"])
+	}
+}
+
+func TestValidateStructPointerFieldIsOptional(t *testing.T) {
+	data := map[string]any{
+		"name": "Ada",
+		"role": "member",
+		"tags": []any{},
+		"city": "London",
+	}
+
+	_, err := voxgigstruct.ValidateStruct(data, reflect.TypeOf(userSpec{}))
+	if err != nil {
+		t.Fatalf("expected a pointer-typed field to be optional when absent, got %v", err)
+	}
+}
+
+func TestValidateAcceptsStructInstanceAsSpec(t *testing.T) {
+	data := map[string]any{
+		"name": "Ada",
+		"role": "admin",
+		"tags": []any{},
+		"city": "London",
+	}
+
+	out, err := voxgigstruct.Validate(data, userSpec{})
+	if err != nil {
+		t.Fatalf("expected Validate to accept a struct instance as spec, got %v", err)
+	}
+	if _, ok := out.(map[string]any); !ok {
+		t.Fatalf("expected a map result, got %T", out)
+	}
+}
+
+func TestValidateStructCachesDerivedSpec(t *testing.T) {
+	data := map[string]any{
+		"name": "Ada",
+		"role": "admin",
+		"tags": []any{},
+		"city": "London",
+	}
+
+	first, err := voxgigstruct.ValidateStruct(data, reflect.TypeOf(userSpec{}))
+	if err != nil {
+		t.Fatalf("expected no error on first call, got %v", err)
+	}
+	second, err := voxgigstruct.ValidateStruct(data, reflect.TypeOf(userSpec{}))
+	if err != nil {
+		t.Fatalf("expected no error on second (cached) call, got %v", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Fatalf("expected repeated calls against the same type to validate identically, got %+v vs %+v", first, second)
+	}
+}
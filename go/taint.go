@@ -0,0 +1,184 @@
+package voxgigstruct
+
+import "strings"
+
+// TaintPolicy maps a security label (e.g. "secret") to an action to take
+// when a value bearing that label would be written to a guarded output
+// path. "deny" is the only action currently recognized; any other value,
+// including an absent entry, is treated as allow.
+type TaintPolicy map[string]string
+
+// taintActionDeny is the TaintPolicy action that causes recordTaint to
+// append an error when a labeled value reaches a guarded output path.
+const taintActionDeny = "deny"
+
+// MatchGlob reports whether path (a dotted path string, e.g.
+// "response.user.email") matches pattern (a dotted glob, e.g.
+// "response.**"). "*" matches exactly one path segment; "**" matches zero
+// or more segments. Used to scope a TaintPolicy to a subset of the output
+// tree via Injection.Guards.
+func MatchGlob(pattern, path string) bool {
+	return matchGlobSegs(strings.Split(pattern, S_DT), strings.Split(path, S_DT))
+}
+
+func matchGlobSegs(pat, seg []string) bool {
+	if len(pat) == 0 {
+		return len(seg) == 0
+	}
+
+	if pat[0] == "**" {
+		if matchGlobSegs(pat[1:], seg) {
+			return true
+		}
+		if len(seg) == 0 {
+			return false
+		}
+		return matchGlobSegs(pat, seg[1:])
+	}
+
+	if len(seg) == 0 {
+		return false
+	}
+	if pat[0] != "*" && pat[0] != seg[0] {
+		return false
+	}
+	return matchGlobSegs(pat[1:], seg[1:])
+}
+
+// joinTaint is the join operation of the taint lattice: the set union of
+// two label sets, used whenever two tainted values flow into the same
+// output (e.g. $MERGE combining several labeled sources).
+func joinTaint(a, b []string) []string {
+	if len(a) == 0 {
+		return b
+	}
+	if len(b) == 0 {
+		return a
+	}
+
+	seen := make(map[string]bool, len(a)+len(b))
+	out := make([]string, 0, len(a)+len(b))
+	for _, l := range a {
+		if !seen[l] {
+			seen[l] = true
+			out = append(out, l)
+		}
+	}
+	for _, l := range b {
+		if !seen[l] {
+			seen[l] = true
+			out = append(out, l)
+		}
+	}
+	return out
+}
+
+// taintLabelsFor looks up the labels configured for srcpath - the same
+// dotted path string used as a $COPY/$MERGE/$EACH/$PACK source argument,
+// or as the reference inside a backtick string interpolation.
+func taintLabelsFor(state *Injection, srcpath string) []string {
+	if state == nil || state.Labels == nil || srcpath == S_MT {
+		return nil
+	}
+	label, has := state.Labels[srcpath]
+	if !has || label == S_MT {
+		return nil
+	}
+	return []string{label}
+}
+
+// taintOutputKey is the state.Meta key under which the labels carried by
+// the value currently being written are recorded.
+func taintOutputKey(path []string) string {
+	return "taint:" + Pathify(path, 1)
+}
+
+// recordTaint joins labels onto whatever is already recorded for state's
+// current output path, then enforces state.Policy against the result.
+// A no-op when state carries neither labels to add nor a pre-existing
+// taint record - the common case for untainted transforms.
+func recordTaint(state *Injection, labels []string) {
+	if state == nil {
+		return
+	}
+
+	key := taintOutputKey(state.Path)
+	existing, _ := state.Meta[key].([]string)
+	merged := joinTaint(existing, labels)
+	if len(merged) == 0 {
+		return
+	}
+
+	if state.Meta == nil {
+		state.Meta = map[string]any{}
+	}
+	state.Meta[key] = merged
+
+	enforceTaintPolicy(state, merged)
+}
+
+// enforceTaintPolicy appends a validation error for every label in labels
+// that state.Policy denies, provided the current output path is matched
+// by state.Guards (or state.Guards is empty, meaning enforce everywhere).
+func enforceTaintPolicy(state *Injection, labels []string) {
+	if state == nil || state.Policy == nil || len(labels) == 0 {
+		return
+	}
+
+	outPath := Pathify(state.Path, 1)
+
+	if len(state.Guards) > 0 {
+		guarded := false
+		for _, g := range state.Guards {
+			if MatchGlob(g, outPath) {
+				guarded = true
+				break
+			}
+		}
+		if !guarded {
+			return
+		}
+	}
+
+	for _, label := range labels {
+		if state.Policy[label] == taintActionDeny {
+			state.addErr(
+				"taint: value labeled \"" + label + "\" denied at output path \"" + outPath + "\"")
+		}
+	}
+}
+
+// TransformTainted is TransformModify with an information-flow policy
+// attached: labels marks known-sensitive source paths (the same path
+// strings used as $COPY/$MERGE/$EACH/$PACK source arguments, or as a
+// backtick interpolation reference), policy says which labels are denied
+// from reaching an output path matched by one of guards (dotted globs
+// over the output tree, e.g. "response.**"; nil means enforce
+// everywhere). A denied write appends to the result's errors via the same
+// $ERRS/$REPORT mechanism as any other validation failure, rather than
+// aborting the transform outright.
+//
+// This is sugar over TransformModify's own extension point: Labels,
+// Policy and Guards travel through extra under the $LABELS/$POLICY/
+// $GUARDS keys, exactly like registering a custom transform function.
+func TransformTainted(
+	data any,
+	spec any,
+	extra any,
+	modify Modify,
+	labels map[string]string,
+	policy TaintPolicy,
+	guards []string,
+) any {
+	tainted := map[string]any{}
+	if extra != nil {
+		for _, kv := range Items(extra) {
+			tainted[StrKey(kv[0])] = kv[1]
+		}
+	}
+	tainted[S_DLABELS] = labels
+	tainted[S_DPOLICY] = policy
+	tainted[S_DGUARDS] = guards
+
+	return TransformModify(data, spec, tainted, modify)
+}
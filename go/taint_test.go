@@ -0,0 +1,133 @@
+package voxgigstruct_test
+
+import (
+	"strings"
+	"testing"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+// taintedTransform runs Transform with a "secret":"deny" policy guarded to
+// response.**, collecting any taint errors into the returned *ListRef - the
+// same pattern TestInjectExprErrorPropagatesToErrs uses to observe errors
+// collected during an Inject/Transform call.
+func taintedTransform(data, spec any, labels map[string]string) (any, *voxgigstruct.ListRef[any]) {
+	errs := voxgigstruct.ListRefCreate[any]()
+	out := voxgigstruct.TransformTainted(data, spec, map[string]any{"$ERRS": errs}, nil, labels,
+		voxgigstruct.TaintPolicy{"secret": "deny"}, []string{"response.**"})
+	return out, errs
+}
+
+func TestMatchGlob(t *testing.T) {
+	cases := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"response.**", "response.user.email", true},
+		{"response.**", "response", true},
+		{"response.*", "response.user.email", false},
+		{"response.*", "response.user", true},
+		{"log.*", "response.user", false},
+	}
+	for _, c := range cases {
+		if got := voxgigstruct.MatchGlob(c.pattern, c.path); got != c.want {
+			t.Errorf("MatchGlob(%q, %q) = %v, want %v", c.pattern, c.path, got, c.want)
+		}
+	}
+}
+
+func TestTransformTaintedCopyDeniedAtGuardedPath(t *testing.T) {
+	data := map[string]any{"response": map[string]any{"ssn": "123-45-6789"}}
+	spec := map[string]any{"response": map[string]any{"ssn": "`$COPY`"}}
+
+	out, errs := taintedTransform(data, spec, map[string]string{"response.ssn": "secret"})
+
+	if len(errs.List) == 0 {
+		t.Fatalf("expected a taint error for a secret copied into response.**, got %+v", out)
+	}
+}
+
+func TestTransformTaintedCopyAllowedOutsideGuard(t *testing.T) {
+	data := map[string]any{"internal": map[string]any{"ssn": "123-45-6789"}}
+	spec := map[string]any{"internal": map[string]any{"ssn": "`$COPY`"}}
+
+	_, errs := taintedTransform(data, spec, map[string]string{"internal.ssn": "secret"})
+
+	if len(errs.List) != 0 {
+		t.Fatalf("expected no taint error outside a guarded path, got %+v", errs.List)
+	}
+}
+
+func TestTransformTaintedMergeJoinsLabels(t *testing.T) {
+	data := map[string]any{
+		"a": map[string]any{"secret": "a-secret"},
+		"b": map[string]any{"secret": "b-secret"},
+	}
+	spec := map[string]any{
+		"response": map[string]any{"`$MERGE`": []any{"`a`", "`b`"}},
+	}
+
+	out, errs := taintedTransform(data, spec, map[string]string{"a": "secret", "b": "secret"})
+
+	if len(errs.List) == 0 {
+		t.Fatalf("expected a taint error from a merge of two secret-labeled sources, got %+v", out)
+	}
+}
+
+func TestTransformTaintedEachPropagatesLabel(t *testing.T) {
+	data := map[string]any{
+		"users": []any{
+			map[string]any{"ssn": "111-11-1111"},
+			map[string]any{"ssn": "222-22-2222"},
+		},
+	}
+	spec := map[string]any{
+		"response": []any{"`$EACH`", "users", map[string]any{"ssn": "`$COPY`"}},
+	}
+
+	out, errs := taintedTransform(data, spec, map[string]string{"users": "secret"})
+
+	if len(errs.List) == 0 {
+		t.Fatalf("expected a taint error propagated through $EACH, got %+v", out)
+	}
+}
+
+func TestTransformTaintedPackPropagatesLabel(t *testing.T) {
+	data := map[string]any{
+		"users": []any{
+			map[string]any{"id": "u1", "ssn": "111-11-1111"},
+		},
+	}
+	spec := map[string]any{
+		"response": map[string]any{
+			"`$PACK`": []any{"users", map[string]any{"ssn": "`$COPY`", "`$KEY`": "id"}},
+		},
+	}
+
+	out, errs := taintedTransform(data, spec, map[string]string{"users": "secret"})
+
+	if len(errs.List) == 0 {
+		t.Fatalf("expected a taint error propagated through $PACK, got %+v", out)
+	}
+}
+
+func TestTransformTaintedStringInterpolationCarriesLabel(t *testing.T) {
+	data := map[string]any{"name": "Ada"}
+	spec := map[string]any{"response": map[string]any{"greeting": "hello `name`"}}
+
+	_, errs := taintedTransform(data, spec, map[string]string{"name": "secret"})
+
+	if len(errs.List) == 0 {
+		t.Fatalf("expected a taint error from interpolating a secret-labeled value")
+	}
+
+	found := false
+	for _, e := range errs.List {
+		if strings.Contains(errMessage(e), "secret") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected a taint error mentioning the secret label, got %+v", errs.List)
+	}
+}
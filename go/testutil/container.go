@@ -0,0 +1,104 @@
+package runner
+
+import (
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+// CheckFunc implements a named custom check, dispatched by SDKUtility.Check
+// when ctx["check"] names it.
+type CheckFunc func(ctx map[string]any) map[string]any
+
+// ContextifyFunc implements a custom contextify step, applied in
+// registration order before the built-in identity contextify.
+type ContextifyFunc func(ctx map[string]any) map[string]any
+
+// Container is a dependency-injection style registry of custom checks,
+// contextify steps, and struct-jose shape validators. NewSDK builds one
+// during construction from the WithCheck/WithContextify/WithValidator
+// options passed to it; this is the extension seam that lets downstream
+// projects add domain-specific checks without forking SDK.
+type Container struct {
+	checks        map[string]CheckFunc
+	contextifiers []ContextifyFunc
+	validators    map[string]any
+}
+
+// NewContainer creates an empty registry. No check is registered in it;
+// SDKUtility.Check falls back to the default ("ZED") check for any name
+// the registry does not resolve, so zero-config SDK behaviour is unchanged.
+func NewContainer() *Container {
+	return &Container{
+		checks:     map[string]CheckFunc{},
+		validators: map[string]any{},
+	}
+}
+
+// Option configures a Container built by NewSDK.
+type Option func(*Container)
+
+// WithCheck registers a named custom check, selected via ctx["check"].
+func WithCheck(name string, fn CheckFunc) Option {
+	return func(c *Container) {
+		c.checks[name] = fn
+	}
+}
+
+// WithContextify registers a custom contextify step, run in registration
+// order before the built-in identity contextify.
+func WithContextify(fn ContextifyFunc) Option {
+	return func(c *Container) {
+		c.contextifiers = append(c.contextifiers, fn)
+	}
+}
+
+// WithValidator registers a struct-jose shape under name, so a test spec
+// entry like { "check": name } runs voxgigstruct.Validate(ctx, shape), with
+// the "check" key left out of ctx and any validation error returned under
+// the "error" key of the result.
+func WithValidator(name string, shape any) Option {
+	return func(c *Container) {
+		c.validators[name] = shape
+		c.checks[name] = func(ctx map[string]any) map[string]any {
+			// "check" is the dispatch key that routed us here, not part of
+			// the data being validated - strip it from a copy so shapes
+			// don't have to account for it.
+			data := make(map[string]any, len(ctx))
+			for k, v := range ctx {
+				if k == "check" {
+					continue
+				}
+				data[k] = v
+			}
+
+			out, err := voxgigstruct.Validate(data, shape)
+			outmap, _ := out.(map[string]any)
+			if outmap == nil {
+				outmap = map[string]any{}
+			}
+			if err != nil {
+				outmap["error"] = err.Error()
+			}
+			return outmap
+		}
+	}
+}
+
+// resolve looks up a registered check by name, if any.
+func (c *Container) resolve(name string) (CheckFunc, bool) {
+	if c == nil {
+		return nil, false
+	}
+	fn, ok := c.checks[name]
+	return fn, ok
+}
+
+// contextify runs all registered contextify steps, in order, over ctx.
+func (c *Container) contextify(ctx map[string]any) map[string]any {
+	if c == nil {
+		return ctx
+	}
+	for _, fn := range c.contextifiers {
+		ctx = fn(ctx)
+	}
+	return ctx
+}
@@ -0,0 +1,32 @@
+package runner
+
+import "testing"
+
+func TestWithCheckDispatch(t *testing.T) {
+	sdk := NewSDK(nil, WithCheck("myrule", func(ctx map[string]any) map[string]any {
+		return map[string]any{"custom": true}
+	}))
+
+	out := sdk.Utility().Check(map[string]any{"check": "myrule"})
+	if out["custom"] != true {
+		t.Errorf("expected custom check to run, got %v", out)
+	}
+}
+
+func TestDefaultCheckUnchanged(t *testing.T) {
+	sdk := NewSDK(map[string]any{"foo": 1})
+
+	out := sdk.Utility().Check(nil)
+	if out["zed"] != "ZED1_0" {
+		t.Errorf("expected unchanged default ZED behaviour, got %v", out)
+	}
+}
+
+func TestWithValidatorDispatch(t *testing.T) {
+	sdk := NewSDK(nil, WithValidator("hasName", map[string]any{"name": "`$STRING`"}))
+
+	out := sdk.Utility().Check(map[string]any{"check": "hasName", "name": "x"})
+	if out["error"] != nil {
+		t.Errorf("expected no error, got %v", out["error"])
+	}
+}
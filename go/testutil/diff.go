@@ -0,0 +1,194 @@
+package runner
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// DiffKind classifies a single structural disagreement found by Diff.
+type DiffKind string
+
+const (
+	DiffMissing        DiffKind = "missing"        // key/index present in expected, absent from actual
+	DiffExtra          DiffKind = "extra"           // key/index present in actual, absent from expected
+	DiffTypeMismatch   DiffKind = "type-mismatch"   // both present, but one is a map/list, the other a scalar
+	DiffScalarMismatch DiffKind = "scalar-mismatch" // both present and comparable, but unequal
+)
+
+// Difference is one disagreement between an expected and actual value,
+// anchored at a dotted path such as "users.2.email" (list indexes are
+// path segments like any key; the root is "$").
+type Difference struct {
+	Path     string
+	Kind     DiffKind
+	Expected any
+	Actual   any
+}
+
+// Diff walks expected and actual together and records every disagreement,
+// replacing the old whole-blob inspect() dump in outFail. It's exported so
+// callers outside this package can reuse the same engine that outFail and
+// handleError's error-object matching use here.
+func Diff(expected any, actual any, structUtil *StructUtility) []Difference {
+	if structUtil == nil {
+		panic("runner: Diff called with a nil structUtil")
+	}
+	var diffs []Difference
+	diffWalk(expected, actual, nil, structUtil, &diffs)
+	return diffs
+}
+
+func diffWalk(expected, actual any, path []string, structUtil *StructUtility, diffs *[]Difference) {
+	expNode := structUtil.IsNode(expected)
+	actNode := structUtil.IsNode(actual)
+
+	if !expNode && !actNode {
+		if !scalarEqual(expected, actual) {
+			*diffs = append(*diffs, Difference{
+				Path:     diffPath(path),
+				Kind:     DiffScalarMismatch,
+				Expected: expected,
+				Actual:   actual,
+			})
+		}
+		return
+	}
+
+	if expNode != actNode {
+		*diffs = append(*diffs, Difference{
+			Path:     diffPath(path),
+			Kind:     DiffTypeMismatch,
+			Expected: expected,
+			Actual:   actual,
+		})
+		return
+	}
+
+	// Index actual by stringified key up front, so presence (vs. present-
+	// but-nil) is exact for both maps and lists, without a second GetPath
+	// traversal per key.
+	actualVals := map[string]any{}
+	actualPresent := map[string]bool{}
+	for _, kv := range structUtil.Items(actual) {
+		key := fmt.Sprintf("%v", kv[0])
+		actualVals[key] = kv[1]
+		actualPresent[key] = true
+	}
+
+	seen := map[string]bool{}
+
+	for _, kv := range structUtil.Items(expected) {
+		key := fmt.Sprintf("%v", kv[0])
+		seen[key] = true
+
+		if !actualPresent[key] {
+			*diffs = append(*diffs, Difference{
+				Path:     diffPath(append(path, key)),
+				Kind:     DiffMissing,
+				Expected: kv[1],
+			})
+			continue
+		}
+
+		diffWalk(kv[1], actualVals[key], append(path, key), structUtil, diffs)
+	}
+
+	for key, val := range actualVals {
+		if seen[key] {
+			continue
+		}
+
+		*diffs = append(*diffs, Difference{
+			Path:   diffPath(append(path, key)),
+			Kind:   DiffExtra,
+			Actual: val,
+		})
+	}
+}
+
+func scalarEqual(a, b any) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+func diffPath(path []string) string {
+	if len(path) == 0 {
+		return "$"
+	}
+	return strings.Join(path, ".")
+}
+
+// noColorEnabled honours the NO_COLOR convention (https://no-color.org).
+func noColorEnabled() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+// RenderDiff formats diffs as unified lines, one per disagreement, e.g.
+//
+//	users.2.email: expected "a@b" got "a@c"
+//
+// Colorized (red "got", green "expected") unless NO_COLOR is set.
+func RenderDiff(diffs []Difference) string {
+	if len(diffs) == 0 {
+		return ""
+	}
+
+	color := !noColorEnabled()
+	var b strings.Builder
+
+	for _, d := range diffs {
+		switch d.Kind {
+		case DiffMissing:
+			fmt.Fprintf(&b, "%s: missing, expected %s\n", d.Path, renderVal(d.Expected, color, true))
+		case DiffExtra:
+			fmt.Fprintf(&b, "%s: unexpected, got %s\n", d.Path, renderVal(d.Actual, color, false))
+		case DiffTypeMismatch:
+			fmt.Fprintf(&b, "%s: expected %s got %s\n", d.Path, renderVal(d.Expected, color, true), renderVal(d.Actual, color, false))
+		case DiffScalarMismatch:
+			fmt.Fprintf(&b, "%s: expected %s got %s\n", d.Path, renderVal(d.Expected, color, true), renderVal(d.Actual, color, false))
+		}
+	}
+
+	return b.String()
+}
+
+// jsonDiffOutput, when passed as `-struct.diff.json` to the test binary,
+// makes FormatDiff emit machine-readable JSON instead of unified text - so
+// external tools (an IDE plugin, a CI dashboard) can consume failures
+// without scraping t.Error strings.
+var jsonDiffOutput = flag.Bool("struct.diff.json", false,
+	"render structural diff failures as JSON instead of unified text")
+
+// RenderDiffJSON encodes diffs as a JSON array.
+func RenderDiffJSON(diffs []Difference) (string, error) {
+	out, err := json.Marshal(diffs)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// FormatDiff renders diffs as unified text, or as JSON when -struct.diff.json
+// was passed to the test binary (see jsonDiffOutput).
+func FormatDiff(diffs []Difference) string {
+	if *jsonDiffOutput {
+		if s, err := RenderDiffJSON(diffs); err == nil {
+			return s
+		}
+	}
+	return RenderDiff(diffs)
+}
+
+func renderVal(v any, color bool, expected bool) string {
+	s := fmt.Sprintf("%#v", v)
+	if !color {
+		return s
+	}
+	if expected {
+		return "\x1b[32m" + s + "\x1b[0m" // green
+	}
+	return "\x1b[31m" + s + "\x1b[0m" // red
+}
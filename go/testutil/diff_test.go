@@ -0,0 +1,105 @@
+package runner
+
+import (
+	"testing"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+func testStructUtil() *StructUtility {
+	return &StructUtility{
+		IsNode:     voxgigstruct.IsNode,
+		Clone:      voxgigstruct.Clone,
+		CloneFlags: voxgigstruct.CloneFlags,
+		GetPath:    voxgigstruct.GetPath,
+		Inject:     voxgigstruct.Inject,
+		Items:      voxgigstruct.Items,
+		Stringify:  voxgigstruct.Stringify,
+		Walk:       voxgigstruct.Walk,
+	}
+}
+
+func TestDiffScalarMismatch(t *testing.T) {
+	expected := map[string]any{"a": float64(1), "b": "x"}
+	actual := map[string]any{"a": float64(1), "b": "y"}
+
+	diffs := Diff(expected, actual, testStructUtil())
+	if len(diffs) != 1 {
+		t.Fatalf("expected 1 diff, got %d: %v", len(diffs), diffs)
+	}
+	if diffs[0].Path != "b" || diffs[0].Kind != DiffScalarMismatch {
+		t.Fatalf("unexpected diff: %+v", diffs[0])
+	}
+}
+
+func TestDiffMissingAndExtra(t *testing.T) {
+	expected := map[string]any{"a": float64(1)}
+	actual := map[string]any{"b": float64(2)}
+
+	diffs := Diff(expected, actual, testStructUtil())
+	if len(diffs) != 2 {
+		t.Fatalf("expected 2 diffs, got %d: %v", len(diffs), diffs)
+	}
+
+	kinds := map[string]DiffKind{}
+	for _, d := range diffs {
+		kinds[d.Path] = d.Kind
+	}
+	if kinds["a"] != DiffMissing {
+		t.Fatalf("expected a to be missing, got %+v", diffs)
+	}
+	if kinds["b"] != DiffExtra {
+		t.Fatalf("expected b to be extra, got %+v", diffs)
+	}
+}
+
+func TestDiffTypeMismatch(t *testing.T) {
+	expected := map[string]any{"a": map[string]any{"x": float64(1)}}
+	actual := map[string]any{"a": float64(1)}
+
+	diffs := Diff(expected, actual, testStructUtil())
+	if len(diffs) != 1 || diffs[0].Kind != DiffTypeMismatch || diffs[0].Path != "a" {
+		t.Fatalf("unexpected diffs: %+v", diffs)
+	}
+}
+
+func TestDiffNestedPath(t *testing.T) {
+	expected := map[string]any{"users": []any{map[string]any{"email": "a@b"}}}
+	actual := map[string]any{"users": []any{map[string]any{"email": "a@c"}}}
+
+	diffs := Diff(expected, actual, testStructUtil())
+	if len(diffs) != 1 || diffs[0].Path != "users.0.email" {
+		t.Fatalf("unexpected diffs: %+v", diffs)
+	}
+}
+
+func TestDiffNoneWhenEqual(t *testing.T) {
+	v := map[string]any{"a": float64(1), "b": []any{"x", "y"}}
+	diffs := Diff(v, v, testStructUtil())
+	if len(diffs) != 0 {
+		t.Fatalf("expected no diffs, got %+v", diffs)
+	}
+}
+
+func TestRenderDiffNoColorEnv(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	diffs := []Difference{{Path: "x", Kind: DiffScalarMismatch, Expected: "a", Actual: "b"}}
+	out := RenderDiff(diffs)
+
+	if out == "" {
+		t.Fatal("expected rendered output")
+	}
+	if contains(out, "\x1b[") {
+		t.Fatalf("expected no ANSI color codes with NO_COLOR set, got %q", out)
+	}
+}
+
+func contains(s, substr string) bool {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		if s[i:i+len(substr)] == substr {
+			return true
+		}
+	}
+	return false
+}
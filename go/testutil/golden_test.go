@@ -0,0 +1,77 @@
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGoldenDirectiveRecognizesShape(t *testing.T) {
+	path, ok := goldenDirective(map[string]any{"$golden": "testdata/golden_case01.json"})
+	if !ok || path != "testdata/golden_case01.json" {
+		t.Fatalf("expected to recognize the $golden directive, got path=%q ok=%v", path, ok)
+	}
+
+	if _, ok := goldenDirective(map[string]any{"a": 1}); ok {
+		t.Fatal("expected a plain map not to be treated as a $golden directive")
+	}
+}
+
+func TestCheckGoldenMatches(t *testing.T) {
+	flags := map[string]bool{"null": true}
+	res := fixJSON(map[string]any{"a": float64(1), "b": []any{"x", "y"}}, flags)
+
+	ok := t.Run("golden", func(t *testing.T) {
+		checkGolden(t, map[string]any{}, res, "testdata/golden_case01.json", flags, testStructUtil())
+	})
+	if !ok {
+		t.Fatal("expected the result to match the golden file")
+	}
+}
+
+// fakeReporter records whether Error was called, without marking the
+// enclosing *testing.T as failed the way a t.Run subtest would.
+type fakeReporter struct {
+	failed bool
+}
+
+func (f *fakeReporter) Error(args ...any) {
+	f.failed = true
+}
+
+func TestCheckGoldenMismatch(t *testing.T) {
+	flags := map[string]bool{"null": true}
+	res := fixJSON(map[string]any{"a": float64(2)}, flags)
+
+	reporter := &fakeReporter{}
+	checkGolden(reporter, map[string]any{}, res, "testdata/golden_case01.json", flags, testStructUtil())
+	if !reporter.failed {
+		t.Fatal("expected the result to mismatch the golden file")
+	}
+}
+
+func TestCheckGoldenUpdateRewritesFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "case.json")
+	if err := os.WriteFile(path, []byte(`{"a": 1}`), 0o644); err != nil {
+		t.Fatalf("seed golden file: %v", err)
+	}
+
+	t.Setenv(goldenUpdateEnv, "1")
+
+	res := map[string]any{"a": float64(2)}
+	ok := t.Run("golden", func(t *testing.T) {
+		checkGolden(t, map[string]any{}, res, path, map[string]bool{"null": true}, testStructUtil())
+	})
+	if !ok {
+		t.Fatal("expected update mode to succeed without comparing")
+	}
+
+	golden, err := loadGolden(path)
+	if err != nil {
+		t.Fatalf("loadGolden after update: %v", err)
+	}
+	if golden.(map[string]any)["a"] != float64(2) {
+		t.Fatalf("expected the golden file to be rewritten with the new result, got %+v", golden)
+	}
+}
@@ -0,0 +1,108 @@
+package runner
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+
+	"github.com/voxgig/struct/service"
+)
+
+// TransportKind selects which backend MakeRunnerWithTransport drives a
+// RunPack's test-set entries against.
+type TransportKind string
+
+const (
+	// TransportInProcess calls the Utility method directly, same as
+	// MakeRunner.
+	TransportInProcess TransportKind = "in-process"
+
+	// TransportGRPC dials a remote Struct gRPC service (see
+	// github.com/voxgig/struct/service) and invokes the method over its
+	// Invoke RPC.
+	TransportGRPC TransportKind = "grpc"
+
+	// TransportHTTP is reserved for a future HTTP-backed Subject; no HTTP
+	// server exists in this repo yet, so MakeRunnerWithTransport rejects it
+	// rather than pretending to support it.
+	TransportHTTP TransportKind = "http"
+)
+
+// Transport configures MakeRunnerWithTransport: Kind selects the backend,
+// Target is the dial address for TransportGRPC (ignored otherwise), and
+// DialOptions are passed through to service.Dial.
+type Transport struct {
+	Kind        TransportKind
+	Target      string
+	DialOptions []grpc.DialOption
+}
+
+// NewGRPCSubject dials the Struct gRPC service at target and returns a
+// Subject that invokes method (e.g. "check") on the remote Utility via the
+// Invoke RPC, passing args positionally - the same calling convention
+// resolveSubject/subjectify give an in-process Utility method.
+func NewGRPCSubject(target string, method string, opts ...grpc.DialOption) (Subject, error) {
+	client, err := service.Dial(target, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("NewGRPCSubject: %w", err)
+	}
+
+	return func(args ...any) (any, error) {
+		return client.Invoke(context.Background(), method, args)
+	}, nil
+}
+
+// MakeRunnerWithTransport is MakeRunner's counterpart for exercising the
+// same spec["basic"] test-set cases against more than one backend - the
+// in-process Utility method, or a remote Struct gRPC service - mirroring
+// the affinity-router integration tester pattern where one JSON spec
+// drives many backend configurations. Every Subject it builds is wrapped
+// with annotateTransport, so a failing entry's error names the transport
+// that produced it.
+func MakeRunnerWithTransport(testfile string, client Client, transport Transport, exts ...string) (func(name string, store any) (*RunPack, error), error) {
+	switch transport.Kind {
+	case "", TransportInProcess:
+		return func(name string, store any) (*RunPack, error) {
+			spec := resolveSpec(name, testfile, exts)
+
+			subject, err := resolveSubject(name, client.Utility())
+			if err != nil {
+				return nil, err
+			}
+
+			return buildRunPack(name, spec, store, client, annotateTransport(subject, TransportInProcess), RunOptions{})
+		}, nil
+
+	case TransportGRPC:
+		return func(name string, store any) (*RunPack, error) {
+			spec := resolveSpec(name, testfile, exts)
+
+			subject, err := NewGRPCSubject(transport.Target, name, transport.DialOptions...)
+			if err != nil {
+				return nil, fmt.Errorf("MakeRunnerWithTransport: grpc: %w", err)
+			}
+
+			return buildRunPack(name, spec, store, client, annotateTransport(subject, TransportGRPC), RunOptions{})
+		}, nil
+
+	case TransportHTTP:
+		return nil, fmt.Errorf("MakeRunnerWithTransport: transport %q is not yet implemented", TransportHTTP)
+
+	default:
+		return nil, fmt.Errorf("MakeRunnerWithTransport: unknown transport kind %q", transport.Kind)
+	}
+}
+
+// annotateTransport wraps subject so any error it returns is prefixed with
+// the transport that produced it, e.g. "[grpc] rpc error: ...", so a
+// multi-transport runset's failure output reveals the backend at a glance.
+func annotateTransport(subject Subject, kind TransportKind) Subject {
+	return func(args ...any) (any, error) {
+		out, err := subject(args...)
+		if err != nil {
+			return out, fmt.Errorf("[%s] %w", kind, err)
+		}
+		return out, nil
+	}
+}
@@ -0,0 +1,88 @@
+package runner
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/voxgig/struct/service"
+)
+
+// dialBufconnSDK starts a bufconn-backed Struct gRPC server wrapping sdk's
+// Utility, and returns dial options that connect to it - the same pattern
+// service_test.TestCheckParity uses, reused here so MakeRunnerWithTransport
+// can be driven against a real (if in-memory) gRPC service.
+func dialBufconnSDK(t *testing.T, sdk *SDK) []grpc.DialOption {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer()
+	service.RegisterStructServer(srv, sdk.Utility())
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	return []grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+}
+
+func TestMakeRunnerWithTransportGRPCMatchesInProcess(t *testing.T) {
+	sdk, err := TestSDK(nil)
+	if err != nil {
+		t.Fatalf("TestSDK: %v", err)
+	}
+
+	dialOpts := dialBufconnSDK(t, sdk)
+
+	inProcess, err := MakeRunnerWithTransport("testdata/grpc_subject.json", sdk, Transport{Kind: TransportInProcess})
+	if err != nil {
+		t.Fatalf("MakeRunnerWithTransport(in-process): %v", err)
+	}
+	inProcessPack, err := inProcess("check", nil)
+	if err != nil {
+		t.Fatalf("in-process runner check: %v", err)
+	}
+
+	grpcRunner, err := MakeRunnerWithTransport("testdata/grpc_subject.json", sdk, Transport{
+		Kind:        TransportGRPC,
+		Target:      "passthrough:///bufnet",
+		DialOptions: dialOpts,
+	})
+	if err != nil {
+		t.Fatalf("MakeRunnerWithTransport(grpc): %v", err)
+	}
+	grpcPack, err := grpcRunner("check", nil)
+	if err != nil {
+		t.Fatalf("grpc runner check: %v", err)
+	}
+
+	for _, transport := range []struct {
+		name string
+		pack *RunPack
+	}{
+		{"in-process", inProcessPack},
+		{"grpc", grpcPack},
+	} {
+		t.Run(transport.name, func(t *testing.T) {
+			transport.pack.RunSet(t, transport.pack.Spec["basic"], transport.pack.Subject)
+		})
+	}
+}
+
+func TestMakeRunnerWithTransportRejectsHTTP(t *testing.T) {
+	sdk, err := TestSDK(nil)
+	if err != nil {
+		t.Fatalf("TestSDK: %v", err)
+	}
+
+	if _, err := MakeRunnerWithTransport("testdata/grpc_subject.json", sdk, Transport{Kind: TransportHTTP}); err == nil {
+		t.Fatalf("expected the unimplemented http transport to error")
+	}
+}
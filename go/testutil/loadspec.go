@@ -0,0 +1,67 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+// defaultSpecExts is the extension search order resolveSpecFile falls back
+// to when MakeRunner isn't given an explicit list.
+var defaultSpecExts = []string{"json", "yaml", "yml", "toml"}
+
+// resolveSpecFile finds the test spec file MakeRunner should load: testfile
+// itself, if it already exists on disk, otherwise - with any extension it
+// has stripped - the first "<base>.<ext>" that exists, trying exts in
+// order. This lets a single MakeRunner(name, client) call site keep working
+// unchanged against a renamed/reformatted spec (e.g. test.json swapped for
+// test.yaml) without editing the call site.
+func resolveSpecFile(testfile string, exts []string) (string, error) {
+	path := filepath.Join(".", testfile)
+
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+
+	if len(exts) == 0 {
+		exts = defaultSpecExts
+	}
+
+	base := strings.TrimSuffix(path, filepath.Ext(path))
+	for _, ext := range exts {
+		candidate := base + "." + strings.TrimPrefix(ext, ".")
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+
+	return "", fmt.Errorf("resolveSpecFile: no test spec found for %q (tried %v)", testfile, exts)
+}
+
+// LoadSpec reads a test spec file from disk, auto-detecting its format
+// (json/yaml/yml/toml) from the file extension and decoding it via
+// voxgigstruct.Unmarshal, so the sizable client-check/basic/inject specs
+// can be authored in YAML (with comments) or TOML while the runner still
+// only ever sees canonical JSON-shaped data.
+func LoadSpec(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+
+	out, err := voxgigstruct.Unmarshal(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("LoadSpec: %s: %w", path, err)
+	}
+
+	spec, ok := out.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("LoadSpec: %s: expected a top-level object", path)
+	}
+	return spec, nil
+}
@@ -0,0 +1,56 @@
+package runner
+
+import (
+	"reflect"
+	"testing"
+)
+
+// The JSON and YAML fixtures describe the same spec, so LoadSpec must
+// produce identical canonical data (map[string]any, float64 numbers) for
+// both, which in turn drives runset identically regardless of authoring
+// format.
+func TestLoadSpecJSONYAMLParity(t *testing.T) {
+	jsonSpec, err := LoadSpec("testdata/loadspec.json")
+	if err != nil {
+		t.Fatalf("LoadSpec(json): %v", err)
+	}
+
+	yamlSpec, err := LoadSpec("testdata/loadspec.yaml")
+	if err != nil {
+		t.Fatalf("LoadSpec(yaml): %v", err)
+	}
+
+	if !reflect.DeepEqual(jsonSpec, yamlSpec) {
+		t.Errorf("json and yaml specs diverge:\njson: %#v\nyaml: %#v", jsonSpec, yamlSpec)
+	}
+}
+
+// resolveSpecFile lets MakeRunner be pointed at a ".json" path even when
+// only a ".yaml" (or ".toml") sibling exists on disk.
+func TestResolveSpecFileExtensionFallback(t *testing.T) {
+	path, err := resolveSpecFile("testdata/resolvespec.json", nil)
+	if err != nil {
+		t.Fatalf("resolveSpecFile: %v", err)
+	}
+	if path != "testdata/resolvespec.yaml" {
+		t.Fatalf("expected fallback to the yaml sibling, got %q", path)
+	}
+
+	spec, err := LoadSpec(path)
+	if err != nil {
+		t.Fatalf("LoadSpec(%q): %v", path, err)
+	}
+
+	// NULLMARK survives the yaml round-trip as a plain string sentinel.
+	set := spec["basic"].(map[string]any)["set"].([]any)
+	entry := set[0].(map[string]any)
+	if entry["in"].(map[string]any)["a"] != NULLMARK {
+		t.Fatalf("expected NULLMARK to survive the round-trip, got %#v", entry["in"])
+	}
+}
+
+func TestResolveSpecFileMissingReturnsError(t *testing.T) {
+	if _, err := resolveSpecFile("testdata/does-not-exist", nil); err == nil {
+		t.Fatal("expected an error when no candidate file exists")
+	}
+}
@@ -0,0 +1,317 @@
+package runner
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+// looksLikePathMatchSpec decides whether entry["match"] should be
+// interpreted by matchPathSpec (objx-style dotted/indexed paths to
+// predicates) rather than today's structural MatchNode walk: a $-prefixed
+// operator at the top level (the whole map is one predicate, applied to
+// base directly), any key containing "." or "[" (a path into base), or a
+// plain key whose own value is itself an all-$-prefixed predicate map
+// (e.g. {"tags": {"$all": ...}}) - all as documented on MatchNode.
+func looksLikePathMatchSpec(check map[string]any) bool {
+	for key, val := range check {
+		if strings.HasPrefix(key, "$") || strings.ContainsAny(key, ".[") {
+			return true
+		}
+		if predMap, ok := val.(map[string]any); ok && isPredicateMap(predMap) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPathSpec evaluates a path-match spec against base: either a single
+// predicate applied to base itself (all keys are "$"-prefixed), or a map of
+// objx-style path -> predicate entries, each resolved independently.
+func matchPathSpec(check map[string]any, base any) (bool, error) {
+	if isPredicateMap(check) {
+		return evalPredicate(check, "$", base)
+	}
+
+	for path, predSpec := range check {
+		vals, err := resolvePathMatches(path, base)
+		if err != nil {
+			return false, fmt.Errorf("MATCH: path %q: %w", path, err)
+		}
+
+		if len(vals) == 0 {
+			if pass, handled := evalExistsOnly(predSpec); handled {
+				if !pass {
+					return false, fmt.Errorf("MATCH: path %q: expected no value, predicate %v", path, predSpec)
+				}
+				continue
+			}
+			return false, fmt.Errorf("MATCH: path %q: no value found", path)
+		}
+
+		for _, val := range vals {
+			pass, err := evalPredicate(predSpec, path, val)
+			if err != nil {
+				return false, err
+			}
+			if !pass {
+				return false, fmt.Errorf(
+					"MATCH: path %q: predicate %v failed for value %v",
+					path, predSpec, val,
+				)
+			}
+		}
+	}
+
+	return true, nil
+}
+
+// evalExistsOnly handles the case where a path resolved to zero values: the
+// only predicate that can still pass is "$exists": false.
+func evalExistsOnly(predSpec any) (pass bool, handled bool) {
+	predMap, ok := predSpec.(map[string]any)
+	if !ok {
+		return false, false
+	}
+	want, hasExists := predMap["$exists"]
+	if !hasExists {
+		return false, false
+	}
+	wantBool, _ := want.(bool)
+	return !wantBool, true
+}
+
+// isPredicateMap reports whether m is itself a predicate (every key is a
+// "$"-prefixed operator), as opposed to a map of path -> predicate entries.
+func isPredicateMap(m map[string]any) bool {
+	if len(m) == 0 {
+		return false
+	}
+	for key := range m {
+		if !strings.HasPrefix(key, "$") {
+			return false
+		}
+	}
+	return true
+}
+
+// resolvePathMatches resolves an objx-style path ("a.b[3].c", "a.b[*].c")
+// against base, reusing the JSONPath subset engine in voxgigstruct (a plain
+// objx path is already valid JSONPath once prefixed with "$."). A path that
+// doesn't exist resolves to zero values rather than an error, so callers
+// can distinguish "absent" from "present but wrong".
+func resolvePathMatches(path string, base any) ([]any, error) {
+	jsonpath := path
+	if !strings.HasPrefix(jsonpath, "$") {
+		jsonpath = "$." + jsonpath
+	}
+
+	matches, err := voxgigstruct.GetPathQuery(jsonpath, base)
+	if err != nil {
+		return nil, err
+	}
+
+	vals := make([]any, len(matches))
+	for i, m := range matches {
+		vals[i] = m.Value
+	}
+	return vals, nil
+}
+
+// evalPredicate applies predSpec to val: either an implicit "$eq" if
+// predSpec is a plain scalar, or the conjunction ("$eq"/"$ne"/"$gt"/"$gte"/
+// "$lt"/"$lte"/"$in"/"$nin"/"$type"/"$len"/"$regex"/"$exists"/"$all"/
+// "$any"/"$not") of every operator key if predSpec is a map.
+func evalPredicate(predSpec any, path string, val any) (bool, error) {
+	predMap, ok := predSpec.(map[string]any)
+	if !ok {
+		return predicateEqual(predSpec, val), nil
+	}
+
+	for op, arg := range predMap {
+		pass, err := evalOperator(op, arg, val)
+		if err != nil {
+			return false, fmt.Errorf("MATCH: path %q: %w", path, err)
+		}
+		if !pass {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func evalOperator(op string, arg any, val any) (bool, error) {
+	switch op {
+	case "$eq":
+		return predicateEqual(arg, val), nil
+	case "$ne":
+		return !predicateEqual(arg, val), nil
+	case "$gt", "$gte", "$lt", "$lte":
+		return evalCompare(op, arg, val)
+	case "$in":
+		return predicateMember(arg, val), nil
+	case "$nin":
+		return !predicateMember(arg, val), nil
+	case "$type":
+		return predicateType(arg, val)
+	case "$len":
+		return evalLen(arg, val)
+	case "$regex":
+		return evalRegex(arg, val)
+	case "$exists":
+		want, _ := arg.(bool)
+		return (val != nil) == want, nil
+	case "$all":
+		return evalQuantifier(arg, val, true)
+	case "$any":
+		return evalQuantifier(arg, val, false)
+	case "$not":
+		pass, err := evalPredicate(arg, "$not", val)
+		return !pass, err
+	default:
+		return false, fmt.Errorf("unknown match operator %q", op)
+	}
+}
+
+func evalCompare(op string, arg any, val any) (bool, error) {
+	a, ok1 := toFloat64(arg)
+	b, ok2 := toFloat64(val)
+	if !ok1 || !ok2 {
+		return false, fmt.Errorf("%s: %v and %v are not both numbers", op, arg, val)
+	}
+	switch op {
+	case "$gt":
+		return b > a, nil
+	case "$gte":
+		return b >= a, nil
+	case "$lt":
+		return b < a, nil
+	case "$lte":
+		return b <= a, nil
+	}
+	return false, fmt.Errorf("unreachable compare operator %q", op)
+}
+
+func evalLen(arg any, val any) (bool, error) {
+	want, ok := toFloat64(arg)
+	if !ok {
+		return false, fmt.Errorf("$len: %v is not a number", arg)
+	}
+
+	v := reflect.ValueOf(val)
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()) == want, nil
+	default:
+		return false, nil
+	}
+}
+
+func evalRegex(arg any, val any) (bool, error) {
+	pat, ok := arg.(string)
+	if !ok {
+		return false, fmt.Errorf("$regex: %v is not a string", arg)
+	}
+	rx, err := regexp.Compile(pat)
+	if err != nil {
+		return false, fmt.Errorf("$regex: %w", err)
+	}
+	return rx.MatchString(predicateStringify(val)), nil
+}
+
+// evalQuantifier implements "$all"/"$any": arg is itself a predicate,
+// applied to every element of val (a slice). all requires every element to
+// pass, any requires at least one.
+func evalQuantifier(arg any, val any, all bool) (bool, error) {
+	v := reflect.ValueOf(val)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return false, nil
+	}
+
+	for i := 0; i < v.Len(); i++ {
+		pass, err := evalPredicate(arg, "$all/$any", v.Index(i).Interface())
+		if err != nil {
+			return false, err
+		}
+		if pass && !all {
+			return true, nil
+		}
+		if !pass && all {
+			return false, nil
+		}
+	}
+	return all, nil
+}
+
+func predicateEqual(a, b any) bool {
+	if af, ok := toFloat64(a); ok {
+		if bf, ok := toFloat64(b); ok {
+			return af == bf
+		}
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func predicateMember(list any, val any) bool {
+	items, ok := list.([]any)
+	if !ok {
+		return false
+	}
+	for _, item := range items {
+		if predicateEqual(item, val) {
+			return true
+		}
+	}
+	return false
+}
+
+func predicateType(want any, val any) (bool, error) {
+	wantStr, ok := want.(string)
+	if !ok {
+		return false, fmt.Errorf("$type: %v is not a string", want)
+	}
+
+	var got string
+	switch v := val.(type) {
+	case nil:
+		got = "null"
+	case bool:
+		got = "bool"
+	case string:
+		got = "string"
+	case float64, int, int64:
+		got = "number"
+	case []any:
+		got = "array"
+	case map[string]any:
+		got = "object"
+	default:
+		got = reflect.TypeOf(v).String()
+	}
+
+	return got == wantStr, nil
+}
+
+func toFloat64(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func predicateStringify(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,84 @@
+package runner
+
+import "testing"
+
+func samplePathBase() map[string]any {
+	return map[string]any{
+		"count":   float64(3),
+		"tags":    []any{"a", "b"},
+		"created": "2024-05-01",
+		"users": []any{
+			map[string]any{"name": "alice", "active": true},
+			map[string]any{"name": "bob", "active": false},
+		},
+	}
+}
+
+func TestMatchNodePathSpecPasses(t *testing.T) {
+	check := map[string]any{
+		"users[0].name":   "alice",
+		"users[*].active": map[string]any{"$type": "bool"},
+		"count":           map[string]any{"$gte": float64(3)},
+		"tags":            map[string]any{"$len": float64(2)},
+		"created":         map[string]any{"$regex": "^2024-"},
+	}
+
+	pass, err := MatchNode(check, samplePathBase(), nil)
+	if err != nil {
+		t.Fatalf("MatchNode: %v", err)
+	}
+	if !pass {
+		t.Fatal("expected match to pass")
+	}
+}
+
+func TestMatchNodePathSpecFails(t *testing.T) {
+	check := map[string]any{
+		"users[0].name": "bob",
+	}
+
+	pass, err := MatchNode(check, samplePathBase(), nil)
+	if pass || err == nil {
+		t.Fatalf("expected match to fail with an error, got pass=%v err=%v", pass, err)
+	}
+}
+
+func TestMatchNodePathSpecExistsFalse(t *testing.T) {
+	check := map[string]any{
+		"missing.field": map[string]any{"$exists": false},
+	}
+
+	pass, err := MatchNode(check, samplePathBase(), nil)
+	if err != nil {
+		t.Fatalf("MatchNode: %v", err)
+	}
+	if !pass {
+		t.Fatal("expected match to pass for an absent path")
+	}
+}
+
+func TestMatchNodeTopLevelPredicate(t *testing.T) {
+	// All keys "$"-prefixed: the whole map is a predicate applied to base
+	// itself, not a map of path -> predicate entries.
+	pass, err := MatchNode(map[string]any{"$in": []any{1, 2, 3}}, float64(2), nil)
+	if err != nil {
+		t.Fatalf("MatchNode: %v", err)
+	}
+	if !pass {
+		t.Fatal("expected top-level predicate to pass")
+	}
+}
+
+func TestMatchNodePathSpecAllQuantifier(t *testing.T) {
+	check := map[string]any{
+		"tags": map[string]any{"$all": map[string]any{"$type": "string"}},
+	}
+
+	pass, err := MatchNode(check, samplePathBase(), nil)
+	if err != nil {
+		t.Fatalf("MatchNode: %v", err)
+	}
+	if !pass {
+		t.Fatal("expected match to pass")
+	}
+}
@@ -0,0 +1,365 @@
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+// RunSpec is the map[string]any shape a runner entry point resolves for a
+// single subject name: {"DEF": ..., "set": [...]}. A type alias, not a new
+// type, so it slots into the exact same map[string]any plumbing MakeRunner
+// already uses - whether loaded from a hand-authored test.json or (as
+// MakeRunnerFromOpenAPI does) synthesized from an OpenAPI document.
+type RunSpec = map[string]any
+
+// OpenAPIOptions configures MakeRunnerFromOpenAPI.
+type OpenAPIOptions struct {
+	// Strict fails MakeRunnerFromOpenAPI outright if any operation has no
+	// usable request/response example anywhere in its requestBody,
+	// parameters, or responses, instead of silently omitting that
+	// operation from the synthesized spec.
+	Strict bool
+}
+
+// MakeRunnerFromOpenAPI is MakeRunner's counterpart for projects that
+// publish an OpenAPI 3.x contract (YAML or JSON) instead of maintaining a
+// parallel test.json fixture. It walks paths.*.<method>, materializes each
+// operation's requestBody/parameters examples and each
+// responses.*.content.*.examples into in/out pairs of a synthesized
+// RunSpec, and exposes them under spec[operationId] - so an existing
+// runset(t, spec[operationId], subject) call site keeps working unchanged.
+// $ref is resolved throughout the document; a oneOf/anyOf example set
+// expands into one sub-case per alternative.
+func MakeRunnerFromOpenAPI(specPath string, sdk *SDK, opts ...OpenAPIOptions) (func(name string, store any) (*RunPack, error), error) {
+	var o OpenAPIOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	doc, err := loadOpenAPIDoc(specPath)
+	if err != nil {
+		return nil, err
+	}
+
+	alltests, err := buildOpenAPISpec(doc, o)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(name string, store any) (*RunPack, error) {
+		spec := specFromAllTests(alltests, name)
+		return buildRunPack(name, spec, store, sdk, nil, RunOptions{})
+	}, nil
+}
+
+// loadOpenAPIDoc reads an OpenAPI document from disk, auto-detecting its
+// format (json/yaml/yml) from the file extension, the same way LoadSpec
+// does for a hand-authored test spec.
+func loadOpenAPIDoc(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+
+	out, err := voxgigstruct.Unmarshal(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("loadOpenAPIDoc: %s: %w", path, err)
+	}
+
+	doc, ok := out.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("loadOpenAPIDoc: %s: expected a top-level object", path)
+	}
+	return doc, nil
+}
+
+// openAPIMethods are the HTTP methods buildOpenAPISpec looks for under
+// each paths.* entry, in the order OpenAPI 3.x itself lists them.
+var openAPIMethods = []string{
+	"get", "put", "post", "delete", "options", "head", "patch", "trace",
+}
+
+// buildOpenAPISpec walks doc's paths, turning every operation that carries
+// an operationId into a spec[operationId] = {"set": [...]} RunSpec.
+func buildOpenAPISpec(doc map[string]any, o OpenAPIOptions) (map[string]any, error) {
+	paths, _ := doc["paths"].(map[string]any)
+
+	alltests := map[string]any{}
+
+	for _, pathKey := range sortedKeys(paths) {
+		pathItem, ok := resolveRef(doc, paths[pathKey]).(map[string]any)
+		if !ok {
+			continue
+		}
+
+		for _, method := range openAPIMethods {
+			opRaw, has := pathItem[method]
+			if !has {
+				continue
+			}
+			op, ok := resolveRef(doc, opRaw).(map[string]any)
+			if !ok {
+				continue
+			}
+
+			operationID, _ := op["operationId"].(string)
+			if operationID == "" {
+				continue
+			}
+
+			set, err := buildOperationSet(doc, op)
+			if err != nil {
+				return nil, fmt.Errorf("buildOpenAPISpec: %s %s: %w", strings.ToUpper(method), pathKey, err)
+			}
+
+			if len(set) == 0 {
+				if o.Strict {
+					return nil, fmt.Errorf("buildOpenAPISpec: %s %s (operationId %q): no example found", strings.ToUpper(method), pathKey, operationID)
+				}
+				continue
+			}
+
+			alltests[operationID] = map[string]any{"set": set}
+		}
+	}
+
+	return alltests, nil
+}
+
+// buildOperationSet materializes one operation's requestBody/parameters
+// examples as "in" and its responses examples as "out", pairing them up
+// into test-set entries. Each requestBody/parameters example is a
+// separate sub-case (so a oneOf/anyOf request schema with N alternatives
+// yields N entries); every sub-case is checked against the first
+// available response example, since an operation's response shape does
+// not vary per request alternative the way its request body can.
+func buildOperationSet(doc map[string]any, op map[string]any) ([]any, error) {
+	ins := operationRequestExamples(doc, op)
+	outs := operationResponseExamples(doc, op)
+
+	if len(ins) == 0 && len(outs) == 0 {
+		return nil, nil
+	}
+
+	var out any
+	if len(outs) > 0 {
+		out = outs[0]
+	}
+
+	if len(ins) == 0 {
+		return []any{map[string]any{"in": map[string]any{}, "out": out}}, nil
+	}
+
+	set := make([]any, 0, len(ins))
+	for _, in := range ins {
+		set = append(set, map[string]any{"in": in, "out": out})
+	}
+	return set, nil
+}
+
+// operationRequestExamples collects every example for op's requestBody,
+// then every example for each of its parameters, each becoming its own
+// "in" candidate.
+func operationRequestExamples(doc map[string]any, op map[string]any) []any {
+	var ins []any
+
+	if rbRaw, has := op["requestBody"]; has {
+		rb, _ := resolveRef(doc, rbRaw).(map[string]any)
+		ins = append(ins, contentExamples(doc, rb)...)
+	}
+
+	if paramsRaw, has := op["parameters"].([]any); has {
+		paramExamples := map[string]any{}
+		for _, pRaw := range paramsRaw {
+			p, ok := resolveRef(doc, pRaw).(map[string]any)
+			if !ok {
+				continue
+			}
+			pname, _ := p["name"].(string)
+			if pname == "" {
+				continue
+			}
+			for _, ex := range schemaExamples(doc, p) {
+				paramExamples[pname] = ex
+				break
+			}
+		}
+		if len(paramExamples) > 0 {
+			ins = append(ins, paramExamples)
+		}
+	}
+
+	return ins
+}
+
+// operationResponseExamples collects every example found across op's
+// responses, preferring 2xx entries (tried in ascending status-code
+// order) and falling back to "default".
+func operationResponseExamples(doc map[string]any, op map[string]any) []any {
+	responses, _ := op["responses"].(map[string]any)
+	if responses == nil {
+		return nil
+	}
+
+	var codes []string
+	for k := range responses {
+		codes = append(codes, k)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		ri, rj := responseCodeRank(codes[i]), responseCodeRank(codes[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return codes[i] < codes[j]
+	})
+
+	var outs []any
+	for _, code := range codes {
+		resp, _ := resolveRef(doc, responses[code]).(map[string]any)
+		outs = append(outs, contentExamples(doc, resp)...)
+	}
+	return outs
+}
+
+// responseCodeRank orders response status codes for operationResponseExamples:
+// 2xx first (ascending), then other explicit codes, then "default" last.
+func responseCodeRank(code string) int {
+	if code == "default" {
+		return 1000
+	}
+	if strings.HasPrefix(code, "2") {
+		return 0
+	}
+	return 1
+}
+
+// contentExamples collects every example under container["content"].*,
+// where container is a requestBody or response object.
+func contentExamples(doc map[string]any, container map[string]any) []any {
+	if container == nil {
+		return nil
+	}
+	content, _ := container["content"].(map[string]any)
+	if content == nil {
+		return nil
+	}
+
+	var exs []any
+	for _, mediaKey := range sortedKeys(content) {
+		media, ok := resolveRef(doc, content[mediaKey]).(map[string]any)
+		if !ok {
+			continue
+		}
+		exs = append(exs, schemaExamples(doc, media)...)
+	}
+	return exs
+}
+
+// schemaExamples collects every example a media-type or parameter object
+// offers: its own "examples" map (in key order, each entry's "value"),
+// its singular "example", or - failing those - its schema's "example",
+// expanded across schema.oneOf/anyOf alternatives when present so each
+// alternative becomes its own example.
+func schemaExamples(doc map[string]any, holder map[string]any) []any {
+	var exs []any
+
+	if examplesRaw, ok := holder["examples"].(map[string]any); ok {
+		for _, key := range sortedKeys(examplesRaw) {
+			ex, ok := resolveRef(doc, examplesRaw[key]).(map[string]any)
+			if !ok {
+				continue
+			}
+			if v, has := ex["value"]; has {
+				exs = append(exs, v)
+			}
+		}
+	}
+
+	if len(exs) > 0 {
+		return exs
+	}
+
+	if v, has := holder["example"]; has {
+		return []any{v}
+	}
+
+	schema, ok := resolveRef(doc, holder["schema"]).(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	if v, has := schema["example"]; has {
+		return []any{v}
+	}
+
+	for _, key := range []string{"oneOf", "anyOf"} {
+		alts, ok := schema[key].([]any)
+		if !ok {
+			continue
+		}
+		for _, altRaw := range alts {
+			alt, ok := resolveRef(doc, altRaw).(map[string]any)
+			if !ok {
+				continue
+			}
+			if v, has := alt["example"]; has {
+				exs = append(exs, v)
+			}
+		}
+		if len(exs) > 0 {
+			return exs
+		}
+	}
+
+	return nil
+}
+
+// resolveRef follows a {"$ref": "#/a/b/c"} node to the value it points at
+// within doc, one hop (OpenAPI refs are not expected to chain through
+// another ref at the top level of these call sites). Anything that isn't
+// a $ref node is returned unchanged.
+func resolveRef(doc map[string]any, node any) any {
+	m, ok := node.(map[string]any)
+	if !ok {
+		return node
+	}
+	ref, ok := m["$ref"].(string)
+	if !ok {
+		return node
+	}
+
+	if !strings.HasPrefix(ref, "#/") {
+		return node
+	}
+
+	var cur any = doc
+	for _, seg := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		seg = strings.ReplaceAll(seg, "~1", "/")
+		seg = strings.ReplaceAll(seg, "~0", "~")
+		curMap, ok := cur.(map[string]any)
+		if !ok {
+			return nil
+		}
+		cur, ok = curMap[seg]
+		if !ok {
+			return nil
+		}
+	}
+	return cur
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,109 @@
+package runner
+
+import (
+	"testing"
+)
+
+func TestBuildOpenAPISpecExpandsOneOfIntoSubCases(t *testing.T) {
+	doc, err := loadOpenAPIDoc("testdata/openapi.json")
+	if err != nil {
+		t.Fatalf("loadOpenAPIDoc: %v", err)
+	}
+
+	alltests, err := buildOpenAPISpec(doc, OpenAPIOptions{})
+	if err != nil {
+		t.Fatalf("buildOpenAPISpec: %v", err)
+	}
+
+	created, ok := alltests["createWidget"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected a createWidget RunSpec, got %#v", alltests["createWidget"])
+	}
+
+	set := created["set"].([]any)
+	if len(set) != 2 {
+		t.Fatalf("expected the requestBody's oneOf to expand into 2 sub-cases, got %d", len(set))
+	}
+
+	for _, entryVal := range set {
+		entry := entryVal.(map[string]any)
+		in := entry["in"].(map[string]any)
+		if in["id"] != "w1" && in["id"] != "w2" {
+			t.Errorf("unexpected sub-case in: %#v", in)
+		}
+		out := entry["out"].(map[string]any)
+		if out["status"] != "ok" {
+			t.Errorf("expected the response example to be used as out, got %#v", out)
+		}
+	}
+}
+
+func TestBuildOpenAPISpecResolvesSingularExampleWithNoRequestBody(t *testing.T) {
+	doc, err := loadOpenAPIDoc("testdata/openapi.json")
+	if err != nil {
+		t.Fatalf("loadOpenAPIDoc: %v", err)
+	}
+
+	alltests, err := buildOpenAPISpec(doc, OpenAPIOptions{})
+	if err != nil {
+		t.Fatalf("buildOpenAPISpec: %v", err)
+	}
+
+	get, ok := alltests["getWidget"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected a getWidget RunSpec, got %#v", alltests["getWidget"])
+	}
+
+	set := get["set"].([]any)
+	if len(set) != 1 {
+		t.Fatalf("expected exactly one sub-case with no requestBody, got %d", len(set))
+	}
+
+	out := set[0].(map[string]any)["out"].(map[string]any)
+	if out["id"] != "w1" {
+		t.Errorf("expected the singular response example, got %#v", out)
+	}
+}
+
+func TestBuildOpenAPISpecOmitsOperationWithNoExample(t *testing.T) {
+	doc, err := loadOpenAPIDoc("testdata/openapi.json")
+	if err != nil {
+		t.Fatalf("loadOpenAPIDoc: %v", err)
+	}
+
+	alltests, err := buildOpenAPISpec(doc, OpenAPIOptions{})
+	if err != nil {
+		t.Fatalf("buildOpenAPISpec: %v", err)
+	}
+
+	if _, has := alltests["deleteWidgetNoExample"]; has {
+		t.Fatalf("expected the example-less operation to be omitted by default")
+	}
+}
+
+func TestBuildOpenAPISpecStrictFailsOnMissingExample(t *testing.T) {
+	doc, err := loadOpenAPIDoc("testdata/openapi.json")
+	if err != nil {
+		t.Fatalf("loadOpenAPIDoc: %v", err)
+	}
+
+	if _, err := buildOpenAPISpec(doc, OpenAPIOptions{Strict: true}); err == nil {
+		t.Fatalf("expected Strict mode to fail on the example-less operation")
+	}
+}
+
+func TestResolveRefFollowsComponentSchemas(t *testing.T) {
+	doc, err := loadOpenAPIDoc("testdata/openapi.json")
+	if err != nil {
+		t.Fatalf("loadOpenAPIDoc: %v", err)
+	}
+
+	resolved := resolveRef(doc, map[string]any{"$ref": "#/components/schemas/Widget"})
+	schema, ok := resolved.(map[string]any)
+	if !ok {
+		t.Fatalf("expected the ref to resolve to a schema object, got %#v", resolved)
+	}
+	if _, has := schema["oneOf"]; !has {
+		t.Errorf("expected the resolved Widget schema to carry oneOf, got %#v", schema)
+	}
+}
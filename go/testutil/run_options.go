@@ -0,0 +1,162 @@
+package runner
+
+import (
+	"encoding/json"
+	"flag"
+	"hash/fnv"
+	"math/rand"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// RunOptions configures how a RunPack built by MakeRunnerWithOptions fans
+// out a spec's test-set entries: parallel execution, deterministic
+// sharding so a large fixture can be split across N CI workers, and a
+// seed controlling the pseudo-random order entries run in. Zero value
+// matches MakeRunner's prior single-threaded, in-order behavior exactly.
+type RunOptions struct {
+	// Parallel, when > 0, calls t.Parallel() inside every entry's t.Run,
+	// fanning entries out across goroutines (actual concurrency is still
+	// capped by go test's own -test.parallel flag, same as the
+	// pre-existing flags["parallel"] spec directive).
+	Parallel int
+
+	// Shard/TotalShards partition entries deterministically: only entries
+	// whose name hashes to Shard (mod TotalShards) run in this process.
+	// TotalShards <= 1 disables sharding, so every entry runs.
+	Shard, TotalShards int
+
+	// Seed controls the pseudo-random shuffle applied to entries before
+	// they run, so an order-dependent failure found on one run can be
+	// pinned down again with the same seed. Zero defers to the
+	// -struct.runner.seed flag; if that's also unset, entries run in
+	// their spec-file order, unchanged from before RunOptions existed.
+	Seed int64
+
+	// Snapshot configures auto-recorded output comparison for entries
+	// whose spec omits "out" - see SnapshotOptions and WithSnapshot.
+	Snapshot SnapshotOptions
+}
+
+// RunOption configures a RunOptions, following the same functional-options
+// shape Container's Option (see container.go) uses for NewSDK - useful for
+// options like WithSnapshot that would rather not require building the
+// RunOptions struct by hand.
+type RunOption func(*RunOptions)
+
+// NewRunOptions builds a RunOptions from a list of RunOption.
+func NewRunOptions(opts ...RunOption) RunOptions {
+	var ro RunOptions
+	for _, opt := range opts {
+		opt(&ro)
+	}
+	return ro
+}
+
+// runnerSeedFlag and runnerReportFlag mirror diff.go's -struct.diff.json
+// flag: package-level flags the test binary can pass without every caller
+// having to plumb them through RunOptions by hand.
+var (
+	runnerSeedFlag = flag.Int64("struct.runner.seed", 0,
+		"seed controlling pseudo-random test-case ordering, for reproducing a sharded/parallel runset failure (overridden by a non-zero RunOptions.Seed)")
+
+	runnerReportFlag = flag.String("struct.runner.report", "",
+		"write a JSON pass/fail/skip/elapsed test report to this path after a runset completes")
+)
+
+// resolveSeed returns ro.Seed if set, else the -struct.runner.seed flag.
+func resolveSeed(ro RunOptions) int64 {
+	if ro.Seed != 0 {
+		return ro.Seed
+	}
+	return *runnerSeedFlag
+}
+
+// shuffleEntries deterministically reorders entries in place using seed,
+// leaving them untouched when seed is zero.
+func shuffleEntries(entries []any, seed int64) {
+	if seed == 0 {
+		return
+	}
+	r := rand.New(rand.NewSource(seed))
+	r.Shuffle(len(entries), func(i, j int) {
+		entries[i], entries[j] = entries[j], entries[i]
+	})
+}
+
+// shardIndex hashes name to a shard in [0, totalShards), so the same case
+// name always lands on the same shard regardless of which worker asks.
+func shardIndex(name string, totalShards int) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	return int(h.Sum32() % uint32(totalShards))
+}
+
+// reportCase is one entry's result in a runReport's JSON output.
+type reportCase struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	ElapsedMS int64  `json:"elapsedMs"`
+}
+
+// runReport accumulates per-case pass/fail/skip/elapsed results across
+// however many goroutines t.Parallel() fans a runset's entries out to,
+// then writes them to -struct.runner.report (if set) and logs a summary
+// once every entry - parallel or not - has finished.
+type runReport struct {
+	Name string
+
+	mu    sync.Mutex
+	Cases []reportCase
+}
+
+// record stores t's outcome for name. Called from each entry's t.Run via
+// defer, so it sees t.Skipped()/t.Failed() as they stood when that entry
+// finished.
+func (r *runReport) record(name string, t *testing.T, elapsed time.Duration) {
+	status := "pass"
+	if t.Skipped() {
+		status = "skip"
+	} else if t.Failed() {
+		status = "fail"
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Cases = append(r.Cases, reportCase{Name: name, Status: status, ElapsedMS: elapsed.Milliseconds()})
+}
+
+// teardown logs a pass/fail/skip summary and, if -struct.runner.report
+// names a path, writes the full per-case report as JSON. Registered via
+// t.Cleanup on the parent *testing.T, which Go only runs once every
+// subtest - including parallel ones - has completed.
+func (r *runReport) teardown(t *testing.T) {
+	var pass, fail, skip int
+	for _, c := range r.Cases {
+		switch c.Status {
+		case "pass":
+			pass++
+		case "fail":
+			fail++
+		case "skip":
+			skip++
+		}
+	}
+	t.Logf("runset %s: %d pass, %d fail, %d skip (%d total)", r.Name, pass, fail, skip, len(r.Cases))
+
+	path := *runnerReportFlag
+	if path == "" {
+		return
+	}
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		t.Errorf("runset %s: failed to encode report: %v", r.Name, err)
+		return
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Errorf("runset %s: failed to write report %q: %v", r.Name, path, err)
+	}
+}
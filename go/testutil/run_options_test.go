@@ -0,0 +1,74 @@
+package runner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMakeRunnerWithOptionsShardsDeterministically(t *testing.T) {
+	sdk, err := TestSDK(nil)
+	if err != nil {
+		t.Fatalf("TestSDK: %v", err)
+	}
+
+	runnerFunc := MakeRunnerWithOptions("testdata/run_options.json", sdk, RunOptions{Shard: 0, TotalShards: 2})
+	pack, err := runnerFunc("check", nil)
+	if err != nil {
+		t.Fatalf("runner check: %v", err)
+	}
+
+	set, ok := pack.Spec["basic"].(map[string]any)["set"].([]any)
+	if !ok || len(set) == 0 {
+		t.Fatalf("expected a non-empty basic test set, got %#v", pack.Spec["basic"])
+	}
+
+	seen := map[string]bool{}
+	for idx, entryVal := range set {
+		entry := entryVal.(map[string]any)
+		name := subtestName(idx, entry)
+		shard := shardIndex(name, 2)
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if other := shardIndex(name, 2); other != shard {
+			t.Fatalf("shardIndex(%q) not stable across calls: %d vs %d", name, shard, other)
+		}
+	}
+}
+
+func TestRunReportWritesJSON(t *testing.T) {
+	sdk, err := TestSDK(nil)
+	if err != nil {
+		t.Fatalf("TestSDK: %v", err)
+	}
+
+	reportPath := filepath.Join(t.TempDir(), "report.json")
+	*runnerReportFlag = reportPath
+	t.Cleanup(func() { *runnerReportFlag = "" })
+
+	runnerFunc := MakeRunnerWithOptions("testdata/run_options.json", sdk, RunOptions{})
+	pack, err := runnerFunc("check", nil)
+	if err != nil {
+		t.Fatalf("runner check: %v", err)
+	}
+
+	t.Run("client-check-basic", func(t *testing.T) {
+		pack.RunSet(t, pack.Spec["basic"], pack.Subject)
+	})
+
+	data, err := os.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("report was not written: %v", err)
+	}
+
+	var report runReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("report is not valid JSON: %v", err)
+	}
+	if len(report.Cases) == 0 {
+		t.Fatalf("expected at least one reported case, got none")
+	}
+}
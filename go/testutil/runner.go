@@ -7,6 +7,7 @@ import (
 
 	"github.com/voxgig/struct"
 
+	"context"
 	"encoding/json"
 	"errors"
 	"os"
@@ -15,6 +16,7 @@ import (
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 	"unicode"
 )
 
@@ -81,50 +83,105 @@ var (
 )
 
 
-// MakeRunner creates a runner function that can be used to run tests
-func MakeRunner(testfile string, client Client) func(name string, store any) (*RunPack, error) {
+// MakeRunner creates a runner function that can be used to run tests.
+// exts, if given, is the extension search order tried when testfile doesn't
+// exist as-is (see resolveSpecFile); it defaults to json, yaml, yml, toml.
+func MakeRunner(testfile string, client Client, exts ...string) func(name string, store any) (*RunPack, error) {
+	return MakeRunnerWithOptions(testfile, client, RunOptions{}, exts...)
+}
 
+// MakeRunnerWithOptions is MakeRunner plus a RunOptions, for fixture sets
+// large enough to need parallel fan-out, CI sharding, or a reproducible
+// seed - see RunOptions for what each field controls. MakeRunner itself is
+// just MakeRunnerWithOptions(..., RunOptions{}, ...), so existing callers
+// are unaffected.
+func MakeRunnerWithOptions(testfile string, client Client, ro RunOptions, exts ...string) func(name string, store any) (*RunPack, error) {
 	return func(name string, store any) (*RunPack, error) {
-		utility := client.Utility()
-		structUtil := utility.Struct()
+		spec := resolveSpec(name, testfile, exts)
+		return buildRunPack(name, spec, store, client, nil, ro)
+	}
+}
 
-		spec := resolveSpec(name, testfile)
+// buildRunPack assembles the RunPack for name against spec: resolving any
+// DEF.client sub-clients and the subject method, and wiring up RunSet/
+// RunSetFlags. Shared by MakeRunner (spec loaded from a test.json-style
+// file) and MakeRunnerFromOpenAPI (spec synthesized from an OpenAPI
+// document), so both expose identical runset(t, spec[...], subject)
+// semantics regardless of where spec came from.
+//
+// subjectOverride, if non-nil, replaces the subject resolveSubject would
+// otherwise derive from client.Utility() - this is how
+// MakeRunnerWithTransport runs the same spec["basic"] cases against a
+// remote (gRPC) Subject instead of the in-process Utility method.
+func buildRunPack(name string, spec map[string]any, store any, client Client, subjectOverride Subject, ro RunOptions) (*RunPack, error) {
+	utility := client.Utility()
+	structUtil := utility.Struct()
+
+	clients, err := resolveClients(spec, store, structUtil, client)
+	if err != nil {
+		return nil, err
+	}
 
-		clients, err := resolveClients(spec, store, structUtil, client)
+	subject := subjectOverride
+	if subject == nil {
+		subject, err = resolveSubject(name, utility)
 		if err != nil {
 			return nil, err
 		}
-		
-		subject, err := resolveSubject(name, utility)
-		if err != nil {
-			return nil, err
+	}
+
+	var runsetFlags RunSetFlags = func(
+		t *testing.T,
+		testspec any,
+		flags map[string]bool,
+		testsubject any,
+	) {
+		if testsubject != nil {
+			subject = subjectify(testsubject)
 		}
 
-		var runsetFlags RunSetFlags = func(
-			t *testing.T,
-			testspec any,
-			flags map[string]bool,
-			testsubject any,
-		) {
-			if testsubject != nil {
-				subject = subjectify(testsubject)
-			}
-			
-			flags = resolveFlags(flags)
-
-			var testspecmap = fixJSON(
-				testspec.(map[string]any),
-				flags,
-			).(map[string]any)
-
-			testset, ok := testspecmap["set"].([]any)
-			if !ok {
-				panic(fmt.Sprintf("No test set in %v", name))
-				return
+		flags = resolveFlags(flags)
+
+		var testspecmap = fixJSON(
+			testspec.(map[string]any),
+			flags,
+		).(map[string]any)
+
+		testset, ok := testspecmap["set"].([]any)
+		if !ok {
+			panic(fmt.Sprintf("No test set in %v", name))
+			return
+		}
+
+		entries := make([]any, len(testset))
+		copy(entries, testset)
+		shuffleEntries(entries, resolveSeed(ro))
+
+		report := &runReport{Name: name}
+		t.Cleanup(func() { report.teardown(t) })
+
+		for idx, entryVal := range entries {
+			entryRaw, _ := entryVal.(map[string]any)
+			_, hasOut := entryRaw["out"]
+
+			entry := resolveEntry(entryVal, flags)
+			entryName := subtestName(idx, entry)
+
+			if ro.TotalShards > 1 && shardIndex(entryName, ro.TotalShards) != ro.Shard {
+				continue
 			}
 
-			for _, entryVal := range testset {
-				entry := resolveEntry(entryVal, flags)
+			t.Run(entryName, func(t *testing.T) {
+				if flags["parallel"] || ro.Parallel > 0 {
+					t.Parallel()
+				}
+
+				start := time.Now()
+				defer func() { report.record(entryName, t, time.Since(start)) }()
+
+				if skipEntry(t, entry) {
+					return
+				}
 
 				testpack, err := resolveTestPack(name, entry, subject, client, clients)
 				if err != nil {
@@ -132,7 +189,7 @@ func MakeRunner(testfile string, client Client) func(name string, store any) (*R
 					return
 				}
 
-				args := resolveArgs(entry, testpack)
+				args := resolveArgs(context.Background(), entry, testpack)
 
 				res, err := testpack.Subject(args...)
 
@@ -142,28 +199,32 @@ func MakeRunner(testfile string, client Client) func(name string, store any) (*R
 				entry["thrown"] = err
 
 				if nil == err {
-					checkResult(t, entry, res, structUtil)
+					if !hasOut && ro.Snapshot.Dir != "" {
+						runSnapshot(t, structUtil, ro.Snapshot, entryName, res)
+					} else {
+						checkResult(t, entry, res, structUtil, flags)
+					}
 				} else {
 					handleError(t, entry, err, structUtil)
 				}
-			}
-		}
-
-		var runset RunSet = func(
-			t *testing.T,
-			testspec any,
-			testsubject any,
-		) {
-			runsetFlags(t, testspec, nil, testsubject)
+			})
 		}
+	}
 
-		return &RunPack{
-			Spec:        spec,
-			RunSet:      runset,
-			RunSetFlags: runsetFlags,
-			Subject:     subject,
-		}, nil
+	var runset RunSet = func(
+		t *testing.T,
+		testspec any,
+		testsubject any,
+	) {
+		runsetFlags(t, testspec, nil, testsubject)
 	}
+
+	return &RunPack{
+		Spec:        spec,
+		RunSet:      runset,
+		RunSetFlags: runsetFlags,
+		Subject:     subject,
+	}, nil
 }
 
 
@@ -177,18 +238,26 @@ func MakeRunner(testfile string, client Client) func(name string, store any) (*R
 func resolveSpec(
 	name string,
 	testfile string,
+	exts []string,
 ) map[string]any {
 
-	data, err := os.ReadFile(filepath.Join(".", testfile))
+	path, err := resolveSpecFile(testfile, exts)
 	if err != nil {
 		panic(err)
 	}
 
-	var alltests map[string]any
-	if err := json.Unmarshal(data, &alltests); err != nil {
+	alltests, err := LoadSpec(path)
+	if err != nil {
 		panic(err)
 	}
 
+	return specFromAllTests(alltests, name)
+}
+
+// specFromAllTests picks name's RunSpec out of alltests: a "primary" map
+// keyed by name, else alltests[name] directly, else (for a single-spec
+// file with no per-name wrapping) alltests itself.
+func specFromAllTests(alltests map[string]any, name string) map[string]any {
 	var spec map[string]any
 
 	// Check if there's a "primary" key that is a map, and if it has our 'name'
@@ -372,11 +441,51 @@ func resolveEntry(entryVal any, flags map[string]bool) map[string]any {
 }
 
 
+// subtestName derives the t.Run name for a test-set entry: its "desc"
+// field, if the spec gives one, else a stable index-based fallback so
+// `go test -run` can still target a specific entry.
+func subtestName(idx int, entry map[string]any) string {
+	if desc, ok := entry["desc"].(string); ok && desc != "" {
+		return desc
+	}
+	return fmt.Sprintf("entry-%d", idx)
+}
+
+
+// skipEntry honours a "skip" field on a test-set entry: a string skips
+// with that reason, a truthy bool skips with no reason. Returns whether
+// the entry was skipped (t.Skip/t.Skipf already stop the goroutine, but
+// the caller still needs to know not to fall through in case of future
+// changes to this function).
+func skipEntry(t *testing.T, entry map[string]any) bool {
+	skip, has := entry["skip"]
+	if !has {
+		return false
+	}
+
+	if reason, ok := skip.(string); ok {
+		if reason == "" {
+			return false
+		}
+		t.Skipf("%s", reason)
+		return true
+	}
+
+	if on, ok := skip.(bool); ok && on {
+		t.Skip()
+		return true
+	}
+
+	return false
+}
+
+
 func checkResult(
 	t *testing.T,
 	entry map[string]any,
 	res any,
 	structUtils *StructUtility,
+	flags map[string]bool,
 ) {
 	// Check if this test expects an output or an error
 	_, hasExpectedErr := entry["err"]
@@ -393,14 +502,19 @@ func checkResult(
 	if entry["match"] == nil || entry["out"] != nil {
 		var cleanRes any
 		if res != nil {
-			flags := map[string]bool{"func": false}
-			cleanRes = structUtils.CloneFlags(res, flags)
+			cloneFlags := map[string]bool{"func": false}
+			cleanRes = structUtils.CloneFlags(res, cloneFlags)
 		} else {
 			cleanRes = res
 		}
 
+		if goldenPath, isGolden := goldenDirective(entry["out"]); isGolden {
+			checkGolden(t, entry, cleanRes, goldenPath, flags, structUtils)
+			return
+		}
+
 		if !reflect.DeepEqual(cleanRes, entry["out"]) {
-			t.Error(outFail(entry, cleanRes, entry["out"]))
+			t.Error(outFail(entry, cleanRes, entry["out"], structUtils))
 			return
 		}
 	}
@@ -426,9 +540,98 @@ func checkResult(
 	}
 }
 
-func outFail(entry any, res any, out any) string {
-	return fmt.Sprintf("Entry:\n%s\nExpected:\n%s\nGot:\n%s\n",
-		inspect(entry), inspect(out), inspect(res))
+// goldenUpdateEnv, when set to a non-empty, non-"0"/"false" value, makes
+// checkGolden rewrite golden files with the actual result instead of
+// comparing against them - the bulk re-approve path for golden diffs.
+const goldenUpdateEnv = "STRUCT_UPDATE_GOLDEN"
+
+// goldenDirective reports whether out is a `{"$golden": "path/to/file"}`
+// directive, returning the referenced path if so.
+func goldenDirective(out any) (path string, ok bool) {
+	m, isMap := out.(map[string]any)
+	if !isMap {
+		return "", false
+	}
+	path, ok = m["$golden"].(string)
+	return path, ok
+}
+
+func goldenUpdateEnabled() bool {
+	v := strings.ToLower(strings.TrimSpace(os.Getenv(goldenUpdateEnv)))
+	return v != "" && v != "0" && v != "false"
+}
+
+// checkGolden compares cleanRes against the golden file at path, or - when
+// STRUCT_UPDATE_GOLDEN is set - rewrites the golden file with cleanRes so
+// the diff can be re-approved in bulk.
+// errorReporter is the subset of *testing.T that checkGolden needs - just
+// enough to report a failure without tying callers to a concrete *testing.T,
+// so tests can assert on a mismatch without tripping Go's subtest-failure-
+// propagates-to-parent behaviour.
+type errorReporter interface {
+	Error(args ...any)
+}
+
+func checkGolden(
+	t errorReporter,
+	entry map[string]any,
+	cleanRes any,
+	path string,
+	flags map[string]bool,
+	structUtil *StructUtility,
+) {
+	if goldenUpdateEnabled() {
+		if err := writeGolden(path, cleanRes); err != nil {
+			t.Error(fmt.Sprintf("golden: failed to update %q: %v", path, err))
+		}
+		return
+	}
+
+	golden, err := loadGolden(path)
+	if err != nil {
+		t.Error(fmt.Sprintf("golden: failed to load %q: %v", path, err))
+		return
+	}
+	golden = fixJSON(golden, flags)
+
+	if !reflect.DeepEqual(cleanRes, golden) {
+		t.Error(outFail(entry, cleanRes, golden, structUtil))
+	}
+}
+
+func loadGolden(path string) (any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	return voxgigstruct.Unmarshal(data, format)
+}
+
+func writeGolden(path string, res any) error {
+	format := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	pretty, err := voxgigstruct.StringifyFormat(res,
+		voxgigstruct.WithFormat(format),
+		voxgigstruct.WithIndent(2),
+	)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(pretty+"\n"), 0o644)
+}
+
+// outFail reports a result mismatch as a structural diff (see Diff) rather
+// than dumping the whole expected/actual blobs, which gets unreadable for
+// large nested results.
+func outFail(entry any, res any, out any, structUtil *StructUtility) string {
+	diffs := Diff(out, res, structUtil)
+	if len(diffs) == 0 {
+		// Diff agrees but reflect.DeepEqual didn't - fall back to the raw dump
+		// (e.g. a type DeepEqual cares about that Diff's node/scalar model doesn't).
+		return fmt.Sprintf("Entry:\n%s\nExpected:\n%s\nGot:\n%s\n",
+			inspect(entry), inspect(out), inspect(res))
+	}
+	return fmt.Sprintf("Entry:\n%s\n%s", inspect(entry), FormatDiff(diffs))
 }
 
 func inspect(val any) string {
@@ -533,6 +736,11 @@ func handleError(
 			}
 		}
 
+	} else if structUtils.IsNode(entryErr) {
+		// entryErr is a structured error-object spec: diff it against the
+		// actual error, same as a result mismatch in checkResult.
+		diffs := Diff(entryErr, fixJSON(testerr, map[string]bool{"null": true}), structUtils)
+		t.Error(FormatDiff(diffs))
 	} else {
 		// If we didn't match, then fail with an error message.
 		t.Error(fmt.Sprintf("ERROR MATCH: [%s] <=> [%s]",
@@ -542,7 +750,7 @@ func handleError(
 	}
 }
 
-func resolveArgs(entry map[string]any, testpack TestPack) []any {
+func resolveArgs(ctx context.Context, entry map[string]any, testpack TestPack) []any {
 	structUtils := testpack.Utility.Struct()
 
 	var args []any
@@ -570,6 +778,7 @@ func resolveArgs(entry map[string]any, testpack TestPack) []any {
 				if m, ok := clonedFirst.(map[string]any); ok {
 					m["client"] = testpack.Client
 					m["utility"] = testpack.Utility
+					m["context"] = ctx
 				}
 			}
 		}
@@ -616,11 +825,22 @@ func resolveTestPack(
 }
 
 
+// MatchNode checks base against check. If check is a map with a "$"-prefixed
+// key, any key containing "." or "[", or a plain key whose value is a map
+// of only "$"-prefixed operators, it's treated as a path-match spec
+// (see looksLikePathMatchSpec/matchPathSpec); otherwise check drives a
+// structural Walk as before, matching scalar-for-scalar.
 func MatchNode(
 	check any,
 	base any,
 	structUtil *StructUtility,
 ) (bool, error) {
+	if checkMap, ok := check.(map[string]any); ok && looksLikePathMatchSpec(checkMap) {
+		// matchPathSpec only reads base, so it doesn't need structUtil.Clone's
+		// defensive copy the way the Walk-driven mode below does.
+		return matchPathSpec(checkMap, base)
+	}
+
 	pass := true
 	var err error = nil
 
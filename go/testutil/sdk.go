@@ -8,8 +8,9 @@ import (
 
 // SDK is a Go implementation of the TypeScript SDK class
 type SDK struct {
-	opts    map[string]any
-	utility *SDKUtility
+	opts      map[string]any
+	container *Container
+	utility   *SDKUtility
 }
 
 // SDKUtility implements the Utility interface
@@ -23,16 +24,31 @@ func (u *SDKUtility) Struct() *StructUtility {
 	return u.structu
 }
 
-// Contextify implements the contextify function
+// Contextify implements the contextify function, running any registered
+// WithContextify steps before returning the context unchanged.
 func (u *SDKUtility) Contextify(ctxmap map[string]any) map[string]any {
-	return ctxmap
+	return u.sdk.container.contextify(ctxmap)
 }
 
-// Check implements the check function
+// Check implements the check function. If ctx["check"] names a check
+// registered via WithCheck/WithValidator, that check is dispatched;
+// otherwise the default zero-config "ZED" behaviour applies.
 func (u *SDKUtility) Check(ctx map[string]any) map[string]any {
+	if ctx != nil {
+		if name, ok := ctx["check"].(string); ok {
+			if fn, found := u.sdk.container.resolve(name); found {
+				return fn(ctx)
+			}
+		}
+	}
+
+	return defaultCheck(u.sdk.opts, ctx)
+}
+
+func defaultCheck(opts map[string]any, ctx map[string]any) map[string]any {
 	zed := "ZED"
-	if u.sdk.opts != nil {
-		if foo, ok := u.sdk.opts["foo"]; ok && foo != nil {
+	if opts != nil {
+		if foo, ok := opts["foo"]; ok && foo != nil {
 			zed += fmt.Sprint(foo)
 		}
 	}
@@ -55,16 +71,24 @@ func (u *SDKUtility) Check(ctx map[string]any) map[string]any {
 	}
 }
 
-// NewSDK creates a new SDK instance with the given options
-func NewSDK(opts map[string]any) *SDK {
+// NewSDK creates a new SDK instance with the given options. Pass
+// WithCheck/WithContextify/WithValidator to register extension-seam
+// behaviour; with no options, the existing zero-config behaviour applies.
+func NewSDK(opts map[string]any, options ...Option) *SDK {
 	if opts == nil {
 		opts = map[string]any{}
 	}
 
+	container := NewContainer()
+	for _, option := range options {
+		option(container)
+	}
+
 	sdk := &SDK{
-		opts: opts,
+		opts:      opts,
+		container: container,
 	}
-	
+
 	// Create the StructUtility
 	structUtil := &StructUtility{
 		IsNode:     voxgigstruct.IsNode,
@@ -87,16 +111,19 @@ func NewSDK(opts map[string]any) *SDK {
 }
 
 // Test creates a new SDK instance (simulating the static async test method)
-func TestSDK(opts map[string]any) (*SDK, error) {
-	return NewSDK(opts), nil
+func TestSDK(opts map[string]any, options ...Option) (*SDK, error) {
+	return NewSDK(opts, options...), nil
 }
 
-// Tester creates a new SDK instance with options or default options
+// Tester creates a new SDK instance with options or default options,
+// carrying over the parent's registered checks/contextifiers/validators.
 func (s *SDK) Tester(opts map[string]any) (*SDK, error) {
 	if opts == nil {
 		opts = s.opts
 	}
-	return NewSDK(opts), nil
+	child := NewSDK(opts)
+	child.container = s.container
+	return child, nil
 }
 
 // Utility returns the utility object
@@ -0,0 +1,147 @@
+package runner
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+// SnapshotOptions configures WithSnapshot's auto-recorded ("golden", but
+// captured from the SDK rather than hand-authored) output comparison: an
+// entry in spec["basic"] that omits "out" entirely has its actual result
+// recorded to Dir/<case>.json on first run, then diffed against that file
+// structurally (key-order-insensitive) on every later run. This is the
+// snapshot-mode counterpart to the existing $golden directive
+// (checkGolden), which requires the spec to name an explicit path; here
+// the path is derived from the case name and the file is created
+// automatically, so large response shapes don't need a hand-written "out"
+// block at all.
+type SnapshotOptions struct {
+	// Dir is the directory snapshot files are read from/written to. Empty
+	// disables snapshotting - an entry with no "out" then goes through the
+	// ordinary result check, exactly as before SnapshotOptions existed
+	// (under the default "null" flag, that check expects a null result).
+	Dir string
+
+	// Update forces every snapshot under Dir to be (re)recorded from the
+	// actual result instead of compared against - the bulk re-approve
+	// path for an intentional response-shape change. Also enabled by
+	// passing -struct.runner.snapshot.update to the test binary.
+	Update bool
+
+	// IgnorePaths are diff paths excluded from comparison, e.g.
+	// "$.timestamp" (the "$."-prefixed form is accepted and stripped) or
+	// plain "timestamp"/"items.0.id" in Diff's own dotted form.
+	IgnorePaths []string
+}
+
+// snapshotUpdateFlag mirrors diff.go's -struct.diff.json flag: a
+// process-wide override so `go test -struct.runner.snapshot.update` can
+// re-record every snapshot without every call site threading
+// SnapshotOptions.Update through by hand.
+var snapshotUpdateFlag = flag.Bool("struct.runner.snapshot.update", false,
+	"(re)record every snapshot from the actual result instead of comparing against it")
+
+// WithSnapshot enables snapshot mode - see SnapshotOptions.
+func WithSnapshot(dir string, update bool) RunOption {
+	return func(ro *RunOptions) {
+		ro.Snapshot.Dir = dir
+		ro.Snapshot.Update = update
+	}
+}
+
+// WithSnapshotIgnore excludes the given diff paths from snapshot
+// comparison, e.g. for a timestamp or request-id field that legitimately
+// changes every run.
+func WithSnapshotIgnore(paths ...string) RunOption {
+	return func(ro *RunOptions) {
+		ro.Snapshot.IgnorePaths = append(ro.Snapshot.IgnorePaths, paths...)
+	}
+}
+
+// runSnapshot records or diffs entryName's result against snap.Dir, per
+// SnapshotOptions' semantics. Failures are reported via t.Error, the same
+// as checkResult/checkGolden do for their comparison modes.
+func runSnapshot(t *testing.T, structUtil *StructUtility, snap SnapshotOptions, entryName string, res any) {
+	path := snapshotFilePath(snap.Dir, entryName)
+
+	if snap.Update || *snapshotUpdateFlag {
+		if err := writeSnapshotFile(path, res); err != nil {
+			t.Errorf("snapshot %q: failed to write %q: %v", entryName, path, err)
+		}
+		return
+	}
+
+	expected, err := loadSnapshotFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		if err := writeSnapshotFile(path, res); err != nil {
+			t.Errorf("snapshot %q: failed to record %q: %v", entryName, path, err)
+		}
+		return
+	}
+	if err != nil {
+		t.Errorf("snapshot %q: failed to load %q: %v", entryName, path, err)
+		return
+	}
+
+	diffs := filterIgnoredDiffs(Diff(expected, res, structUtil), snap.IgnorePaths)
+	if len(diffs) > 0 {
+		t.Errorf("snapshot %q mismatch (%s):\n%s", entryName, path, FormatDiff(diffs))
+	}
+}
+
+// snapshotFilePath derives Dir/<case>.json from entryName, replacing
+// filesystem-hostile characters (a subtest name may contain "/" to mirror
+// t.Run's own "/"-joined hierarchy) with "_".
+func snapshotFilePath(dir, entryName string) string {
+	safe := strings.NewReplacer("/", "_", "\\", "_", " ", "_").Replace(entryName)
+	return filepath.Join(dir, safe+".json")
+}
+
+func loadSnapshotFile(path string) (any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return voxgigstruct.Unmarshal(data, "json")
+}
+
+func writeSnapshotFile(path string, val any) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	pretty, err := voxgigstruct.StringifyFormat(val,
+		voxgigstruct.WithFormat("json"),
+		voxgigstruct.WithIndent(2),
+	)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(pretty+"\n"), 0o644)
+}
+
+// filterIgnoredDiffs drops any Difference whose Path matches one of
+// ignorePaths (after stripping a "$." prefix, if present).
+func filterIgnoredDiffs(diffs []Difference, ignorePaths []string) []Difference {
+	if len(ignorePaths) == 0 {
+		return diffs
+	}
+
+	ignore := make(map[string]bool, len(ignorePaths))
+	for _, p := range ignorePaths {
+		ignore[strings.TrimPrefix(p, "$.")] = true
+	}
+
+	kept := make([]Difference, 0, len(diffs))
+	for _, d := range diffs {
+		if !ignore[d.Path] {
+			kept = append(kept, d)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,60 @@
+package runner
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func runCheckOnce(t *testing.T, sdk *SDK, ro RunOptions) {
+	t.Helper()
+
+	runnerFunc := MakeRunnerWithOptions("testdata/snapshot.json", sdk, ro)
+	pack, err := runnerFunc("check", nil)
+	if err != nil {
+		t.Fatalf("runner check: %v", err)
+	}
+
+	set := pack.Spec["basic"].(map[string]any)["set"].([]any)
+	entry := set[0].(map[string]any)
+	delete(entry, "out")
+
+	pack.RunSet(t, pack.Spec["basic"], pack.Subject)
+}
+
+func TestSnapshotRecordsThenComparesOnSubsequentRuns(t *testing.T) {
+	sdk, err := TestSDK(nil)
+	if err != nil {
+		t.Fatalf("TestSDK: %v", err)
+	}
+
+	dir := t.TempDir()
+	ro := NewRunOptions(WithSnapshot(dir, false))
+
+	t.Run("first run records the snapshot", func(t *testing.T) {
+		runCheckOnce(t, sdk, ro)
+	})
+
+	entries, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		t.Fatalf("glob snapshots: %v", err)
+	}
+	if len(entries) == 0 {
+		t.Fatalf("expected at least one snapshot file to be recorded in %s", dir)
+	}
+
+	t.Run("second run compares against the recorded snapshot", func(t *testing.T) {
+		runCheckOnce(t, sdk, ro)
+	})
+}
+
+func TestSnapshotUpdateFlagForcesRerecording(t *testing.T) {
+	sdk, err := TestSDK(nil)
+	if err != nil {
+		t.Fatalf("TestSDK: %v", err)
+	}
+
+	dir := t.TempDir()
+
+	runCheckOnce(t, sdk, NewRunOptions(WithSnapshot(dir, false)))
+	runCheckOnce(t, sdk, NewRunOptions(WithSnapshot(dir, true)))
+}
@@ -0,0 +1,348 @@
+/* Copyright (c) 2025 Voxgig Ltd. MIT LICENSE. */
+
+package voxgigstruct
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ValidateStreamOptions configures ValidateStream. Named distinctly from
+// TransformStream's StreamOptions - that name is already taken in this
+// package for the worker-pool element pipeline - even though both serve
+// an analogous "bound memory while walking a stream" role.
+type ValidateStreamOptions struct {
+	// MaxErrors stops the walk and closes the error channel once this
+	// many errors have been emitted. Zero means unlimited.
+	MaxErrors int
+
+	// MaxDepth stops the walk, emitting one final error, once object/array
+	// nesting exceeds this many levels. Zero means unlimited.
+	MaxDepth int
+
+	// MaxBytes stops the walk, emitting one final error, once dec has
+	// consumed more than this many input bytes (dec.InputOffset()). Zero
+	// means unlimited.
+	MaxBytes int64
+
+	// Collect builds and returns the validated output tree, like
+	// ValidateCollect, into *CollectInto once the returned channel is
+	// closed. When false, validated values are discarded as soon as
+	// they're checked, so memory use is bounded by nesting depth alone
+	// rather than by the size of data - useful for pure validation of a
+	// huge payload. Ignored if CollectInto is nil.
+	Collect bool
+
+	// CollectInto receives the validated output tree when Collect is
+	// true. Only meaningful once the caller has drained the error
+	// channel to completion.
+	CollectInto *any
+}
+
+// ValidateStream drives a token-level walk of dec against spec, without
+// materializing the whole decoded document the way ValidateCollect does:
+// it maintains an explicit path stack alongside the decoder's own
+// object/array nesting, matches each token against the sub-spec at the
+// same path, and emits a *ValidationError on the returned channel for
+// every failure found, closing the channel when the walk finishes (or
+// early-aborts via opts.MaxErrors/MaxDepth/MaxBytes).
+//
+// Structural matching (which sub-spec applies to which key/index) is done
+// directly against the token stream, so object/array nesting of arbitrary
+// depth never materializes more than one level's worth of siblings at a
+// time. Scalar leaves are validated by handing the single decoded token
+// and its matching leaf spec (e.g. "`$STRING`", "`$ONE`" alternatives) to
+// ValidateCollectStructured - the same entry point the $ONE/$EXACT
+// validators themselves already use to validate one value in isolation -
+// so $STRING/$NUMBER/$ONE/$EXACT and friends are reused as-is rather than
+// reimplemented, while memory stays bounded to one leaf value (plus its
+// spec) rather than the whole document.
+//
+// The second return value is non-nil only if the walk could not start at
+// all (e.g. dec errors before any token is read); errors found *during*
+// the walk are reported solely via the channel.
+func ValidateStream(dec *json.Decoder, spec any, opts ValidateStreamOptions) (<-chan *ValidationError, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *ValidationError, 16)
+
+	go func() {
+		defer close(ch)
+		w := &streamValidator{dec: dec, opts: opts, ch: ch}
+		out := w.walkValue(tok, spec, nil, 0)
+		if opts.Collect && opts.CollectInto != nil {
+			*opts.CollectInto = out
+		}
+	}()
+
+	return ch, nil
+}
+
+type streamValidator struct {
+	dec      *json.Decoder
+	opts     ValidateStreamOptions
+	ch       chan *ValidationError
+	errCount int
+	stopped  bool
+}
+
+func (w *streamValidator) emit(e *ValidationError) {
+	if w.stopped {
+		return
+	}
+	w.ch <- e
+	w.errCount++
+	if w.opts.MaxErrors > 0 && w.errCount >= w.opts.MaxErrors {
+		w.stopped = true
+	}
+}
+
+// overLimit checks MaxDepth/MaxBytes, emitting one final error and
+// stopping the walk the first time either is exceeded.
+func (w *streamValidator) overLimit(path []any, depth int) bool {
+	if w.stopped {
+		return true
+	}
+	if w.opts.MaxDepth > 0 && depth > w.opts.MaxDepth {
+		w.emit(&ValidationError{
+			Path: path, Pointer: jsonPointer(path),
+			Message: fmt.Sprintf("Exceeded max depth %d at field %s", w.opts.MaxDepth, Pathify(pathAsStrings(path), 0)),
+			Severity: SeverityError,
+		})
+		w.stopped = true
+		return true
+	}
+	if w.opts.MaxBytes > 0 && w.dec.InputOffset() > w.opts.MaxBytes {
+		w.emit(&ValidationError{
+			Path: path, Pointer: jsonPointer(path),
+			Message:  fmt.Sprintf("Exceeded max input size %d bytes", w.opts.MaxBytes),
+			Severity: SeverityError,
+		})
+		w.stopped = true
+		return true
+	}
+	return false
+}
+
+func (w *streamValidator) walkValue(tok any, spec any, path []any, depth int) any {
+	if w.overLimit(path, depth) {
+		return nil
+	}
+
+	if delim, ok := tok.(json.Delim); ok {
+		switch delim {
+		case '{':
+			return w.walkObject(spec, path, depth)
+		case '[':
+			return w.walkArray(spec, path, depth)
+		}
+	}
+
+	return w.walkScalar(tok, spec, path)
+}
+
+// specMapFor resolves spec to a map[string]any shape, if it is one; an
+// open "`$OBJECT`" or "`$ANY`" leaf spec (or no spec at all) means any
+// object shape is accepted, with no key-level checking.
+func specMapFor(spec any) (map[string]any, bool) {
+	m, ok := spec.(map[string]any)
+	return m, ok
+}
+
+// specKeyLookup finds key's sub-spec in specMap, honoring the same
+// optional "key?" suffix convention validation() uses.
+func specKeyLookup(specMap map[string]any, key string) (any, bool) {
+	if v, ok := specMap[key]; ok {
+		return v, true
+	}
+	if v, ok := specMap[key+"?"]; ok {
+		return v, true
+	}
+	return nil, false
+}
+
+func (w *streamValidator) walkObject(spec any, path []any, depth int) any {
+	specMap, closed := specMapFor(spec)
+	if closed {
+		if GetProp(specMap, "`$OPEN`") == true {
+			closed = false
+		}
+	}
+
+	seen := map[string]bool{}
+	var out map[string]any
+	if w.opts.Collect {
+		out = map[string]any{}
+	}
+
+	for w.dec.More() {
+		if w.stopped {
+			break
+		}
+
+		keyTok, err := w.dec.Token()
+		if err != nil {
+			w.emit(&ValidationError{Path: path, Pointer: jsonPointer(path), Message: err.Error(), Severity: SeverityError})
+			break
+		}
+		key, _ := keyTok.(string)
+		seen[key] = true
+
+		childPath := append(append([]any{}, path...), key)
+
+		// Unknown-key detection happens the moment each key arrives,
+		// rather than after the whole object is buffered.
+		subspec, found := (any)(nil), false
+		if specMap != nil {
+			subspec, found = specKeyLookup(specMap, key)
+		}
+		if closed && specMap != nil && len(specMap) > 0 && !found {
+			w.emit(&ValidationError{
+				Path: childPath, Pointer: jsonPointer(childPath),
+				Message:  "Unexpected key at field " + Pathify(pathAsStrings(childPath), 1) + ": " + key,
+				Severity: SeverityError,
+			})
+		}
+
+		valTok, err := w.dec.Token()
+		if err != nil {
+			w.emit(&ValidationError{Path: childPath, Pointer: jsonPointer(childPath), Message: err.Error(), Severity: SeverityError})
+			break
+		}
+
+		v := w.walkValue(valTok, subspec, childPath, depth+1)
+		if w.opts.Collect {
+			out[key] = v
+		}
+	}
+
+	// Consume the closing '}' (or whatever the decoder errors on).
+	if _, err := w.dec.Token(); err != nil && !w.stopped {
+		w.emit(&ValidationError{Path: path, Pointer: jsonPointer(path), Message: err.Error(), Severity: SeverityError})
+	}
+
+	if closed && specMap != nil {
+		missing := []string{}
+		for pkey := range specMap {
+			if pkey == "`$OPEN`" {
+				continue
+			}
+			fieldKey := pkey
+			if len(pkey) > 0 && pkey[len(pkey)-1] == '?' {
+				continue
+			}
+			if !seen[fieldKey] {
+				missing = append(missing, fieldKey)
+			}
+		}
+		if len(missing) > 0 {
+			w.emit(&ValidationError{
+				Path: path, Pointer: jsonPointer(path),
+				Message:  "Missing required keys at field " + Pathify(pathAsStrings(path), 1) + ": " + _join(toAnySlice(missing), ", "),
+				Severity: SeverityError,
+			})
+		}
+	}
+
+	if w.opts.Collect {
+		return out
+	}
+	return nil
+}
+
+// childSpecFor resolves the per-element template from a `$CHILD`-wrapped
+// array spec (["`$CHILD`", template], the same convention ValidateStruct
+// generates for slice fields), or returns spec itself unresolved if it
+// isn't one, so a bare element-shape spec can still be used directly.
+func childSpecFor(spec any) any {
+	list, ok := spec.([]any)
+	if !ok || len(list) < 2 {
+		return spec
+	}
+	if marker, ok := list[0].(string); ok && marker == "`$CHILD`" {
+		return list[1]
+	}
+	return spec
+}
+
+func (w *streamValidator) walkArray(spec any, path []any, depth int) any {
+	elemSpec := childSpecFor(spec)
+
+	var out []any
+	if w.opts.Collect {
+		out = []any{}
+	}
+
+	idx := 0
+	for w.dec.More() {
+		if w.stopped {
+			break
+		}
+
+		valTok, err := w.dec.Token()
+		if err != nil {
+			w.emit(&ValidationError{Path: path, Pointer: jsonPointer(path), Message: err.Error(), Severity: SeverityError})
+			break
+		}
+
+		childPath := append(append([]any{}, path...), idx)
+		v := w.walkValue(valTok, elemSpec, childPath, depth+1)
+		if w.opts.Collect {
+			out = append(out, v)
+		}
+		idx++
+	}
+
+	if _, err := w.dec.Token(); err != nil && !w.stopped {
+		w.emit(&ValidationError{Path: path, Pointer: jsonPointer(path), Message: err.Error(), Severity: SeverityError})
+	}
+
+	if w.opts.Collect {
+		return out
+	}
+	return nil
+}
+
+// walkScalar validates a single decoded leaf token against spec by
+// handing both to ValidateCollectStructured - the same way $ONE/$EXACT
+// already validate one candidate value against one alternative shape in
+// isolation - then rebases the resulting errors' paths onto path, the
+// leaf's real position in the overall document.
+func (w *streamValidator) walkScalar(tok any, spec any, path []any) any {
+	if spec == nil {
+		return tok
+	}
+
+	terrs := ListRefCreate[*ValidationError]()
+	val, _ := ValidateCollectStructured(tok, spec, nil, terrs)
+
+	for _, e := range terrs.List {
+		e.Path = path
+		e.Pointer = jsonPointer(path)
+		w.emit(e)
+	}
+
+	if w.opts.Collect {
+		return val
+	}
+	return nil
+}
+
+func pathAsStrings(path []any) []string {
+	out := make([]string, len(path))
+	for i, p := range path {
+		out[i] = StrKey(p)
+	}
+	return out
+}
+
+func toAnySlice(ss []string) []any {
+	out := make([]any, len(ss))
+	for i, s := range ss {
+		out[i] = s
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+package voxgigstruct_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+func drainStream(t *testing.T, ch <-chan *voxgigstruct.ValidationError) []*voxgigstruct.ValidationError {
+	t.Helper()
+	var errs []*voxgigstruct.ValidationError
+	for e := range ch {
+		errs = append(errs, e)
+	}
+	return errs
+}
+
+func TestValidateStreamAcceptsMatchingDocument(t *testing.T) {
+	dec := json.NewDecoder(strings.NewReader(`{"name": "Ada", "age": 30}`))
+	spec := map[string]any{"name": "`$STRING`", "age": "`$NUMBER`"}
+
+	var out any
+	ch, err := voxgigstruct.ValidateStream(dec, spec, voxgigstruct.ValidateStreamOptions{Collect: true, CollectInto: &out})
+	if err != nil {
+		t.Fatalf("ValidateStream: %v", err)
+	}
+
+	errs := drainStream(t, ch)
+	if len(errs) != 0 {
+		t.Fatalf("expected no errors, got %+v", errs)
+	}
+
+	got, ok := out.(map[string]any)
+	if !ok || got["name"] != "Ada" {
+		t.Fatalf("expected collected output to include name, got %+v", out)
+	}
+}
+
+func TestValidateStreamReportsTypeMismatchWithPath(t *testing.T) {
+	dec := json.NewDecoder(strings.NewReader(`{"name": 123}`))
+	spec := map[string]any{"name": "`$STRING`"}
+
+	ch, err := voxgigstruct.ValidateStream(dec, spec, voxgigstruct.ValidateStreamOptions{})
+	if err != nil {
+		t.Fatalf("ValidateStream: %v", err)
+	}
+
+	errs := drainStream(t, ch)
+	if len(errs) != 1 {
+		t.Fatalf("expected exactly one error, got %d: %+v", len(errs), errs)
+	}
+	if errs[0].Pointer != "/name" {
+		t.Errorf("expected pointer \"/name\", got %q", errs[0].Pointer)
+	}
+}
+
+func TestValidateStreamDetectsUnexpectedKey(t *testing.T) {
+	dec := json.NewDecoder(strings.NewReader(`{"name": "Ada", "extra": 1}`))
+	spec := map[string]any{"name": "`$STRING`"}
+
+	ch, err := voxgigstruct.ValidateStream(dec, spec, voxgigstruct.ValidateStreamOptions{})
+	if err != nil {
+		t.Fatalf("ValidateStream: %v", err)
+	}
+
+	errs := drainStream(t, ch)
+	found := false
+	for _, e := range errs {
+		if strings.Contains(e.Message, "extra") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected an unexpected-key error mentioning \"extra\", got %+v", errs)
+	}
+}
+
+func TestValidateStreamValidatesChildTemplateArray(t *testing.T) {
+	dec := json.NewDecoder(strings.NewReader(`{"tags": ["a", 1, "c"]}`))
+	spec := map[string]any{"tags": []any{"`$CHILD`", "`$STRING`"}}
+
+	ch, err := voxgigstruct.ValidateStream(dec, spec, voxgigstruct.ValidateStreamOptions{})
+	if err != nil {
+		t.Fatalf("ValidateStream: %v", err)
+	}
+
+	errs := drainStream(t, ch)
+	if len(errs) != 1 {
+		t.Fatalf("expected exactly one error for the non-string element, got %d: %+v", len(errs), errs)
+	}
+	if errs[0].Pointer != "/tags/1" {
+		t.Errorf("expected pointer \"/tags/1\", got %q", errs[0].Pointer)
+	}
+}
+
+func TestValidateStreamStopsAtMaxErrors(t *testing.T) {
+	dec := json.NewDecoder(strings.NewReader(`{"a": 1, "b": 2, "c": 3}`))
+	spec := map[string]any{"a": "`$STRING`", "b": "`$STRING`", "c": "`$STRING`"}
+
+	ch, err := voxgigstruct.ValidateStream(dec, spec, voxgigstruct.ValidateStreamOptions{MaxErrors: 1})
+	if err != nil {
+		t.Fatalf("ValidateStream: %v", err)
+	}
+
+	errs := drainStream(t, ch)
+	if len(errs) != 1 {
+		t.Fatalf("expected the walk to stop after MaxErrors, got %d errors: %+v", len(errs), errs)
+	}
+}
@@ -0,0 +1,160 @@
+/* Copyright (c) 2025 Voxgig Ltd. MIT LICENSE. */
+
+package voxgigstruct
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// ValidationError is a structured, path-aware validation failure: where it
+// happened (Path, the same path GetPath walks, and Pointer, the same path
+// in RFC 6901 JSON Pointer form), what went wrong (Code, reusing the
+// "V0xxx" whence codes already passed to _invalidTypeMsg; Expected; Got;
+// GotType; and Value, the raw offending value), and a human-readable
+// Message, with a Severity so callers can distinguish hard failures from
+// warnings.
+type ValidationError struct {
+	Path     []any  `json:"path"`
+	Pointer  string `json:"pointer,omitempty"`
+	Code     string `json:"code,omitempty"`
+	Expected string `json:"expected,omitempty"`
+	Got      string `json:"got,omitempty"`
+	GotType  string `json:"gotType,omitempty"`
+	Value    any    `json:"value,omitempty"`
+	Message  string `json:"message"`
+	Severity string `json:"severity"`
+}
+
+// jsonPointer renders path (as produced by pathAsAny) as an RFC 6901 JSON
+// Pointer: each segment is escaped ("~" -> "~0", "/" -> "~1", in that
+// order) and prefixed with "/". An empty path yields "", the whole-
+// document pointer.
+func jsonPointer(path []any) string {
+	if len(path) == 0 {
+		return S_MT
+	}
+
+	var b strings.Builder
+	for _, seg := range path {
+		b.WriteByte('/')
+		s := StrKey(seg)
+		s = strings.ReplaceAll(s, "~", "~0")
+		s = strings.ReplaceAll(s, "/", "~1")
+		b.WriteString(s)
+	}
+	return b.String()
+}
+
+// ErrorFormatter renders the errors collected during a ValidateCollect/
+// ValidateCollectStructured call into the final error's message, in place
+// of the default pipe-joined string. Supplied via extra["$FORMAT"] (see
+// package format for ready-made rule-set-driven formatters).
+type ErrorFormatter func(errs *ListRef[*ValidationError]) string
+
+// Severity values for ValidationError.Severity.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// ValidationReport collects ValidationErrors produced during a Validate /
+// ValidateCollect call, giving callers machine-readable, field-level
+// diagnostics instead of opaque strings.
+type ValidationReport struct {
+	Errors []*ValidationError
+}
+
+// NewValidationReport creates an empty report.
+func NewValidationReport() *ValidationReport {
+	return &ValidationReport{Errors: make([]*ValidationError, 0)}
+}
+
+// Add appends a ValidationError to the report.
+func (r *ValidationReport) Add(err *ValidationError) {
+	if err.Severity == "" {
+		err.Severity = SeverityError
+	}
+	r.Errors = append(r.Errors, err)
+}
+
+// Merge appends all errors from other into r.
+func (r *ValidationReport) Merge(other *ValidationReport) {
+	if other == nil {
+		return
+	}
+	r.Errors = append(r.Errors, other.Errors...)
+}
+
+// ByPath returns all errors whose Path matches path exactly.
+func (r *ValidationReport) ByPath(path []any) []*ValidationError {
+	out := make([]*ValidationError, 0)
+	for _, e := range r.Errors {
+		if pathEqual(e.Path, path) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// AsJSON renders the report as a JSON array of ValidationError objects.
+func (r *ValidationReport) AsJSON() (string, error) {
+	b, err := json.Marshal(r.Errors)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// problemDocument is the RFC 7807-style JSON shape ValidationReport's
+// MarshalJSON emits, so a report can be returned directly as an HTTP
+// "application/problem+json" body.
+type problemDocument struct {
+	Type   string             `json:"type"`
+	Title  string             `json:"title"`
+	Errors []*ValidationError `json:"errors"`
+}
+
+// MarshalJSON implements json.Marshaler, rendering r as an RFC 7807
+// problem document rather than its bare Errors field - unlike AsJSON,
+// which keeps the older plain-array shape for existing callers.
+func (r *ValidationReport) MarshalJSON() ([]byte, error) {
+	title := "No validation errors"
+	if len(r.Errors) > 0 {
+		title = "Validation failed"
+	}
+	return json.Marshal(problemDocument{
+		Type:   "about:blank",
+		Title:  title,
+		Errors: r.Errors,
+	})
+}
+
+func pathEqual(a, b []any) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if StrKey(a[i]) != StrKey(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// pathAsAny converts a []string traversal path (as stored on Injection) to
+// the []any representation used by ValidationError, stripping a leading
+// S_DTOP ("$TOP") segment - the virtual root parent InjectDescend wraps
+// every value in - so rendered paths/pointers read as "/a", not
+// "/$TOP/a".
+func pathAsAny(path []string) []any {
+	if len(path) > 0 && path[0] == S_DTOP {
+		path = path[1:]
+	}
+
+	out := make([]any, len(path))
+	for i, p := range path {
+		out[i] = p
+	}
+	return out
+}
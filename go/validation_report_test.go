@@ -0,0 +1,120 @@
+package voxgigstruct_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+func TestValidateReportPathAware(t *testing.T) {
+	out, report, err := voxgigstruct.ValidateReport(
+		map[string]any{"a": "A"},
+		map[string]any{"a": "`$NUMBER`"},
+	)
+
+	if err == nil {
+		t.Fatalf("expected a validation error")
+	}
+
+	if len(report.Errors) != 1 {
+		t.Fatalf("expected exactly one structured error, got %d", len(report.Errors))
+	}
+
+	got := report.Errors[0]
+	if got.Severity != voxgigstruct.SeverityError {
+		t.Errorf("expected severity %q, got %q", voxgigstruct.SeverityError, got.Severity)
+	}
+
+	byPath := report.ByPath(got.Path)
+	if len(byPath) != 1 {
+		t.Errorf("expected ByPath to find the error by its own path, got %d", len(byPath))
+	}
+
+	js, err := report.AsJSON()
+	if err != nil {
+		t.Fatalf("AsJSON: %v", err)
+	}
+	if js == "" {
+		t.Errorf("expected non-empty JSON report")
+	}
+
+	_ = out
+}
+
+func TestValidateReportErrorFieldsPopulated(t *testing.T) {
+	_, report, err := voxgigstruct.ValidateReport(
+		map[string]any{"a": "A"},
+		map[string]any{"a": "`$NUMBER`"},
+	)
+	if err == nil {
+		t.Fatalf("expected a validation error")
+	}
+
+	got := report.Errors[0]
+	if got.Pointer != "/a" {
+		t.Errorf("expected pointer \"/a\", got %q", got.Pointer)
+	}
+	if got.Expected != "number" {
+		t.Errorf("expected expected type \"number\", got %q", got.Expected)
+	}
+	if got.Got != "A" {
+		t.Errorf("expected got value \"A\", got %q", got.Got)
+	}
+	if got.GotType != "string" {
+		t.Errorf("expected got type \"string\", got %q", got.GotType)
+	}
+	if got.Value != "A" {
+		t.Errorf("expected raw value \"A\", got %+v", got.Value)
+	}
+}
+
+func TestValidationReportMarshalJSONIsProblemDocument(t *testing.T) {
+	_, report, err := voxgigstruct.ValidateReport(
+		map[string]any{"a": "A"},
+		map[string]any{"a": "`$NUMBER`"},
+	)
+	if err == nil {
+		t.Fatalf("expected a validation error")
+	}
+
+	b, merr := json.Marshal(report)
+	if merr != nil {
+		t.Fatalf("MarshalJSON: %v", merr)
+	}
+
+	var doc map[string]any
+	if uerr := json.Unmarshal(b, &doc); uerr != nil {
+		t.Fatalf("unmarshal problem document: %v", uerr)
+	}
+	if doc["type"] != "about:blank" {
+		t.Errorf("expected type \"about:blank\", got %+v", doc["type"])
+	}
+	if doc["title"] != "Validation failed" {
+		t.Errorf("expected title \"Validation failed\", got %+v", doc["title"])
+	}
+	errsField, ok := doc["errors"].([]any)
+	if !ok || len(errsField) != 1 {
+		t.Errorf("expected a single-element errors array, got %+v", doc["errors"])
+	}
+}
+
+func TestValidateCollectStructuredAccumulatesValidationErrors(t *testing.T) {
+	collected := voxgigstruct.ListRefCreate[*voxgigstruct.ValidationError]()
+	_, err := voxgigstruct.ValidateCollectStructured(
+		map[string]any{"a": "A"},
+		map[string]any{"a": "`$NUMBER`"},
+		nil,
+		collected,
+	)
+	if err == nil {
+		t.Fatalf("expected a validation error")
+	}
+	if len(collected.List) != 1 {
+		t.Fatalf("expected exactly one collected structured error, got %d", len(collected.List))
+	}
+	if !strings.Contains(collected.List[0].Message, "field a") {
+		t.Errorf("expected the collected error to mention field a, got %q", collected.List[0].Message)
+	}
+}
@@ -0,0 +1,479 @@
+package voxgigstruct
+
+import (
+	"errors"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// regexCache memoizes compiled $REGEX patterns so repeated validation of
+// the same shape against many records does not recompile the pattern
+// every time.
+var regexCache sync.Map
+
+func _compileRegexCached(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := regexCache.Load(pattern); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	regexCache.Store(pattern, re)
+	return re, nil
+}
+
+// _scalarValidatorArgs checks that a ['$NAME', ...] directive sits as the
+// first element of its enclosing list - the same constraint $ONE and
+// $EXACT enforce - and, if so, stops further iteration of the directive
+// list's own arguments and returns them (everything after the '$NAME'
+// marker).
+func _scalarValidatorArgs(name string, state *Injection) ([]any, bool) {
+	if !IsList(state.Parent) || state.KeyI != 0 {
+		state.addErr("The $" + name + " validator at field " +
+			Pathify(state.Path, 1, 1) +
+			" must be the first element of an array.")
+		return nil, false
+	}
+
+	state.KeyI = len(state.Keys)
+
+	parentSlice, ok := state.Parent.([]any)
+	if !ok {
+		return nil, false
+	}
+
+	return parentSlice[1:], true
+}
+
+// _scalarValidatorRewrite replaces the ['$NAME', ...] directive list at
+// its grandparent/grandkey with current, the same clean-up $ONE and
+// $EXACT do once they have decided the field passes or fails - leaving
+// the final shape free of validator directives.
+func _scalarValidatorRewrite(state *Injection, current any) {
+	grandparent := GetProp(state.Nodes, len(state.Nodes)-2)
+	grandkey := GetProp(state.Path, len(state.Path)-2)
+
+	SetProp(grandparent, grandkey, current)
+	state.Parent = current
+
+	state.Path = state.Path[:len(state.Path)-1]
+	state.Key = state.Path[len(state.Path)-1]
+}
+
+// _rangeDesc renders a ['$RANGE', min, max] or ['$LENGTH', min, max]
+// bound pair for error messages, e.g. "[1, 10]" or "[1, *]" when max is
+// open.
+func _rangeDesc(min, max any) string {
+	minDesc := "*"
+	if min != nil {
+		minDesc = _stringifyValue(min)
+	}
+	maxDesc := "*"
+	if max != nil {
+		maxDesc = _stringifyValue(max)
+	}
+	return "[" + minDesc + ", " + maxDesc + "]"
+}
+
+// Format: ['`$REGEX`', 'pattern']
+var validate_REGEX Injector = func(
+	state *Injection,
+	_val any,
+	current any,
+	ref *string,
+	store any,
+) any {
+	if S_MVAL != state.Mode {
+		return nil
+	}
+
+	args, ok := _scalarValidatorArgs("REGEX", state)
+	if !ok {
+		return nil
+	}
+	_scalarValidatorRewrite(state, current)
+
+	str, isStr := current.(string)
+	if !isStr {
+		state.addErr(_invalidTypeMsg(state.Path, S_string, Typify(current), current, "V0301"))
+		return nil
+	}
+
+	pattern, _ := GetProp(args, 0).(string)
+	re, err := _compileRegexCached(pattern)
+	if err != nil {
+		state.addErr("Invalid $REGEX pattern at field " + Pathify(state.Path, 1) +
+			": " + err.Error())
+		return nil
+	}
+
+	if !re.MatchString(str) {
+		state.addErr(_invalidTypeMsg(state.Path, "match of `"+pattern+"`", Typify(current), current, "V0301"))
+	}
+
+	return nil
+}
+
+// Format: ['`$ENUM`', v1, v2, ...]
+var validate_ENUM Injector = func(
+	state *Injection,
+	_val any,
+	current any,
+	ref *string,
+	store any,
+) any {
+	if S_MVAL != state.Mode {
+		return nil
+	}
+
+	args, ok := _scalarValidatorArgs("ENUM", state)
+	if !ok {
+		return nil
+	}
+	_scalarValidatorRewrite(state, current)
+
+	for _, member := range args {
+		if reflect.DeepEqual(member, current) {
+			return nil
+		}
+	}
+
+	mapped := make([]string, len(args))
+	for i, member := range args {
+		mapped[i] = _stringifyValue(member)
+	}
+
+	desc := "equal to " + strings.Join(mapped, ", ")
+	if len(mapped) > 1 {
+		desc = "one of " + strings.Join(mapped, ", ")
+	}
+
+	state.addErr(_invalidTypeMsg(state.Path, desc, Typify(current), current, "V0302"))
+	return nil
+}
+
+// Format: ['`$RANGE`', min, max] - either bound may be nil for an open range.
+var validate_RANGE Injector = func(
+	state *Injection,
+	_val any,
+	current any,
+	ref *string,
+	store any,
+) any {
+	if S_MVAL != state.Mode {
+		return nil
+	}
+
+	args, ok := _scalarValidatorArgs("RANGE", state)
+	if !ok {
+		return nil
+	}
+	_scalarValidatorRewrite(state, current)
+
+	num, err := _toFloat64(current)
+	if err != nil {
+		state.addErr(_invalidTypeMsg(state.Path, S_number, Typify(current), current, "V0303"))
+		return nil
+	}
+
+	min := GetProp(args, 0)
+	max := GetProp(args, 1)
+
+	inRange := true
+	if min != nil {
+		if minf, err := _toFloat64(min); err == nil && num < minf {
+			inRange = false
+		}
+	}
+	if max != nil {
+		if maxf, err := _toFloat64(max); err == nil && num > maxf {
+			inRange = false
+		}
+	}
+
+	if !inRange {
+		state.addErr(_invalidTypeMsg(state.Path, "in range "+_rangeDesc(min, max), Typify(current), current, "V0303"))
+	}
+
+	return nil
+}
+
+// _lengthOf returns the length of a string, list or map value under
+// $LENGTH, and false for anything else.
+func _lengthOf(val any) (int, bool) {
+	switch v := val.(type) {
+	case string:
+		return len(v), true
+	case []any:
+		return len(v), true
+	case map[string]any:
+		return len(v), true
+	default:
+		return 0, false
+	}
+}
+
+// Format: ['`$LENGTH`', min, max] - either bound may be nil for an open range.
+var validate_LENGTH Injector = func(
+	state *Injection,
+	_val any,
+	current any,
+	ref *string,
+	store any,
+) any {
+	if S_MVAL != state.Mode {
+		return nil
+	}
+
+	args, ok := _scalarValidatorArgs("LENGTH", state)
+	if !ok {
+		return nil
+	}
+	_scalarValidatorRewrite(state, current)
+
+	length, ok := _lengthOf(current)
+	if !ok {
+		state.addErr(_invalidTypeMsg(state.Path, "a string, array or object", Typify(current), current, "V0304"))
+		return nil
+	}
+
+	min := GetProp(args, 0)
+	max := GetProp(args, 1)
+
+	inRange := true
+	if min != nil {
+		if minf, err := _toFloat64(min); err == nil && float64(length) < minf {
+			inRange = false
+		}
+	}
+	if max != nil {
+		if maxf, err := _toFloat64(max); err == nil && float64(length) > maxf {
+			inRange = false
+		}
+	}
+
+	if !inRange {
+		state.addErr(_invalidTypeMsg(state.Path, "length in range "+_rangeDesc(min, max), Typify(current), current, "V0304"))
+	}
+
+	return nil
+}
+
+// formatCheckers maps a $FORMAT name to the function that checks it,
+// seeded with the built-in formats and extensible via RegisterFormat.
+var formatCheckers sync.Map
+
+var reEmailFormat = regexp.MustCompile(`^[^\s@]+@[^\s@]+\.[^\s@]+$`)
+var reUUIDFormat = regexp.MustCompile(`(?i)^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+func isFormatEmail(s string) bool {
+	return reEmailFormat.MatchString(s)
+}
+
+func isFormatUUID(s string) bool {
+	return reUUIDFormat.MatchString(s)
+}
+
+func isFormatURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.Scheme != S_MT && u.Host != S_MT
+}
+
+func isFormatDateTime(s string) bool {
+	_, err := time.Parse(time.RFC3339, s)
+	return err == nil
+}
+
+func isFormatIPv4(s string) bool {
+	ip := net.ParseIP(s)
+	return ip != nil && ip.To4() != nil
+}
+
+func isFormatIPv6(s string) bool {
+	ip := net.ParseIP(s)
+	return ip != nil && ip.To4() == nil
+}
+
+func init() {
+	formatCheckers.Store("email", isFormatEmail)
+	formatCheckers.Store("uuid", isFormatUUID)
+	formatCheckers.Store("url", isFormatURL)
+	formatCheckers.Store("date-time", isFormatDateTime)
+	formatCheckers.Store("ipv4", isFormatIPv4)
+	formatCheckers.Store("ipv6", isFormatIPv6)
+}
+
+// RegisterFormat registers a custom $FORMAT checker under name, overriding
+// any built-in checker already registered for that name.
+func RegisterFormat(name string, fn func(string) bool) {
+	formatCheckers.Store(name, fn)
+}
+
+// Format: ['`$FORMAT`', 'email'|'uuid'|'url'|'date-time'|'ipv4'|'ipv6'|custom]
+var validate_FORMAT Injector = func(
+	state *Injection,
+	_val any,
+	current any,
+	ref *string,
+	store any,
+) any {
+	if S_MVAL != state.Mode {
+		return nil
+	}
+
+	args, ok := _scalarValidatorArgs("FORMAT", state)
+	if !ok {
+		return nil
+	}
+	_scalarValidatorRewrite(state, current)
+
+	str, isStr := current.(string)
+	if !isStr {
+		state.addErr(_invalidTypeMsg(state.Path, S_string, Typify(current), current, "V0305"))
+		return nil
+	}
+
+	name, _ := GetProp(args, 0).(string)
+	checker, has := formatCheckers.Load(name)
+	if !has {
+		state.addErr("Unknown $FORMAT name `" + name + "` at field " + Pathify(state.Path, 1))
+		return nil
+	}
+
+	fn, ok := checker.(func(string) bool)
+	if !ok || !fn(str) {
+		state.addErr(_invalidTypeMsg(state.Path, name+" format", Typify(current), current, "V0305"))
+	}
+
+	return nil
+}
+
+// typeValidators maps a reflect.Type's String() (e.g. "time.Time",
+// "*big.Int") to the function that validates a value against it, seeded
+// with the built-in registrations and extensible via RegisterTypeValidator.
+var typeValidators sync.Map
+
+// RegisterTypeValidator registers fn to validate values declared against
+// t (by `['$TYPE', "pkg.Type"]`, where "pkg.Type" is t.String()),
+// overriding any validator already registered for that type. Safe to call
+// concurrently with validation itself - typeValidators is a sync.Map, and
+// registration is expected to happen once at startup before validation
+// begins.
+func RegisterTypeValidator(t reflect.Type, fn func(val any, state *Injection) error) {
+	typeValidators.Store(t.String(), fn)
+}
+
+func init() {
+	RegisterTypeValidator(reflect.TypeOf(time.Time{}), validateTypeTime)
+	RegisterTypeValidator(reflect.TypeOf(url.URL{}), validateTypeURL)
+	RegisterTypeValidator(reflect.TypeOf(netip.Addr{}), validateTypeNetipAddr)
+	RegisterTypeValidator(reflect.TypeOf((*big.Int)(nil)), validateTypeBigInt)
+}
+
+func validateTypeTime(val any, state *Injection) error {
+	switch v := val.(type) {
+	case time.Time:
+		return nil
+	case string:
+		_, err := time.Parse(time.RFC3339, v)
+		return err
+	default:
+		return errInvalidDomainValue
+	}
+}
+
+func validateTypeURL(val any, state *Injection) error {
+	switch v := val.(type) {
+	case url.URL, *url.URL:
+		return nil
+	case string:
+		u, err := url.Parse(v)
+		if err != nil {
+			return err
+		}
+		if u.Scheme == S_MT || u.Host == S_MT {
+			return errInvalidDomainValue
+		}
+		return nil
+	default:
+		return errInvalidDomainValue
+	}
+}
+
+func validateTypeNetipAddr(val any, state *Injection) error {
+	switch v := val.(type) {
+	case netip.Addr:
+		return nil
+	case string:
+		_, err := netip.ParseAddr(v)
+		return err
+	default:
+		return errInvalidDomainValue
+	}
+}
+
+func validateTypeBigInt(val any, state *Injection) error {
+	switch v := val.(type) {
+	case *big.Int:
+		return nil
+	case string:
+		if _, ok := new(big.Int).SetString(v, 10); !ok {
+			return errInvalidDomainValue
+		}
+		return nil
+	default:
+		return errInvalidDomainValue
+	}
+}
+
+var errInvalidDomainValue = errors.New("value does not satisfy the registered type")
+
+// Format: ['`$TYPE`', "pkg.Type"] - pkg.Type is a reflect.Type.String()
+// token (e.g. "time.Time", "*big.Int") registered via
+// RegisterTypeValidator. Built-in registrations cover time.Time (RFC 3339
+// parse), net/url.URL, netip.Addr, and *big.Int.
+var validate_TYPE Injector = func(
+	state *Injection,
+	_val any,
+	current any,
+	ref *string,
+	store any,
+) any {
+	if S_MVAL != state.Mode {
+		return nil
+	}
+
+	args, ok := _scalarValidatorArgs("TYPE", state)
+	if !ok {
+		return nil
+	}
+	_scalarValidatorRewrite(state, current)
+
+	typeName, _ := GetProp(args, 0).(string)
+	checker, has := typeValidators.Load(typeName)
+	if !has {
+		state.addErr("Unknown $TYPE name `" + typeName + "` at field " + Pathify(state.Path, 1))
+		return nil
+	}
+
+	fn, ok := checker.(func(val any, state *Injection) error)
+	if !ok {
+		return nil
+	}
+
+	if err := fn(current, state); err != nil {
+		state.addErr(_invalidTypeMsg(state.Path, typeName+" ("+err.Error()+")", Typify(current), current, "V0306"))
+	}
+
+	return nil
+}
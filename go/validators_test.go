@@ -0,0 +1,189 @@
+package voxgigstruct_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+func TestValidateRegexAcceptsAndRejects(t *testing.T) {
+	spec := map[string]any{"code": []any{"`$REGEX`", "^[A-Z]{3}$"}}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"code": "ABC"}, spec); err != nil {
+		t.Fatalf("expected a matching string to pass, got %v", err)
+	}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"code": "abc"}, spec); err == nil {
+		t.Fatalf("expected a non-matching string to fail")
+	} else if !strings.Contains(err.Error(), "code") {
+		t.Fatalf("expected error to mention the field path, got %v", err)
+	}
+}
+
+func TestValidateEnumAcceptsAndRejects(t *testing.T) {
+	spec := map[string]any{"status": []any{"`$ENUM`", "open", "closed"}}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"status": "open"}, spec); err != nil {
+		t.Fatalf("expected an allowed enum member to pass, got %v", err)
+	}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"status": "pending"}, spec); err == nil {
+		t.Fatalf("expected a value outside the enum to fail")
+	}
+}
+
+func TestValidateRangeAcceptsAndRejects(t *testing.T) {
+	spec := map[string]any{"age": []any{"`$RANGE`", float64(0), float64(120)}}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"age": float64(30)}, spec); err != nil {
+		t.Fatalf("expected an in-range number to pass, got %v", err)
+	}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"age": float64(200)}, spec); err == nil {
+		t.Fatalf("expected an out-of-range number to fail")
+	}
+}
+
+func TestValidateRangeOpenBound(t *testing.T) {
+	spec := map[string]any{"score": []any{"`$RANGE`", float64(0), nil}}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"score": float64(1000000)}, spec); err != nil {
+		t.Fatalf("expected an open upper bound to accept any large number, got %v", err)
+	}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"score": float64(-1)}, spec); err == nil {
+		t.Fatalf("expected a number below the lower bound to fail")
+	}
+}
+
+func TestValidateLengthAppliesToStringsListsAndMaps(t *testing.T) {
+	spec := map[string]any{
+		"name": []any{"`$LENGTH`", float64(1), float64(10)},
+	}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"name": "Ada"}, spec); err != nil {
+		t.Fatalf("expected a string within length bounds to pass, got %v", err)
+	}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"name": ""}, spec); err == nil {
+		t.Fatalf("expected an empty string below the minimum length to fail")
+	}
+
+	listSpec := map[string]any{"tags": []any{"`$LENGTH`", float64(1), float64(2)}}
+	if _, err := voxgigstruct.Validate(map[string]any{
+		"tags": []any{"a", "b", "c"},
+	}, listSpec); err == nil {
+		t.Fatalf("expected a list exceeding the maximum length to fail")
+	}
+}
+
+func TestValidateFormatBuiltins(t *testing.T) {
+	spec := map[string]any{"email": []any{"`$FORMAT`", "email"}}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"email": "ada@example.com"}, spec); err != nil {
+		t.Fatalf("expected a valid email to pass, got %v", err)
+	}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"email": "not-an-email"}, spec); err == nil {
+		t.Fatalf("expected an invalid email to fail")
+	}
+}
+
+func TestRegisterFormatAddsCustomChecker(t *testing.T) {
+	voxgigstruct.RegisterFormat("even-digits", func(s string) bool {
+		return len(s)%2 == 0
+	})
+
+	spec := map[string]any{"code": []any{"`$FORMAT`", "even-digits"}}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"code": "1234"}, spec); err != nil {
+		t.Fatalf("expected a custom format to accept a matching value, got %v", err)
+	}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"code": "123"}, spec); err == nil {
+		t.Fatalf("expected a custom format to reject a non-matching value")
+	}
+}
+
+func TestValidateTypeBuiltinTime(t *testing.T) {
+	spec := map[string]any{"at": []any{"`$TYPE`", "time.Time"}}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"at": "2025-01-02T15:04:05Z"}, spec); err != nil {
+		t.Fatalf("expected an RFC 3339 timestamp to pass, got %v", err)
+	}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"at": time.Now()}, spec); err != nil {
+		t.Fatalf("expected an already-typed time.Time to pass, got %v", err)
+	}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"at": "not-a-time"}, spec); err == nil {
+		t.Fatalf("expected a non-RFC-3339 string to fail")
+	}
+}
+
+func TestValidateTypeBuiltinURL(t *testing.T) {
+	spec := map[string]any{"site": []any{"`$TYPE`", "url.URL"}}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"site": "https://example.com/path"}, spec); err != nil {
+		t.Fatalf("expected a valid URL to pass, got %v", err)
+	}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"site": "not a url"}, spec); err == nil {
+		t.Fatalf("expected an invalid URL to fail")
+	}
+}
+
+func TestValidateTypeBuiltinNetipAddr(t *testing.T) {
+	spec := map[string]any{"ip": []any{"`$TYPE`", "netip.Addr"}}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"ip": "192.0.2.1"}, spec); err != nil {
+		t.Fatalf("expected a valid IP address to pass, got %v", err)
+	}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"ip": "not-an-ip"}, spec); err == nil {
+		t.Fatalf("expected an invalid IP address to fail")
+	}
+}
+
+func TestValidateTypeBuiltinBigInt(t *testing.T) {
+	spec := map[string]any{"amount": []any{"`$TYPE`", "*big.Int"}}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"amount": "123456789012345678901234567890"}, spec); err != nil {
+		t.Fatalf("expected a big integer literal to pass, got %v", err)
+	}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"amount": "not-a-number"}, spec); err == nil {
+		t.Fatalf("expected a non-numeric string to fail")
+	}
+}
+
+func TestValidateTypeUnknownNameFails(t *testing.T) {
+	spec := map[string]any{"x": []any{"`$TYPE`", "no.such.Type"}}
+
+	if _, err := voxgigstruct.Validate(map[string]any{"x": "whatever"}, spec); err == nil {
+		t.Fatalf("expected an unregistered $TYPE name to fail")
+	}
+}
+
+func TestRegisterTypeValidatorAddsCustomType(t *testing.T) {
+	type widget struct{ ID string }
+
+	voxgigstruct.RegisterTypeValidator(reflect.TypeOf(widget{}), func(val any, state *voxgigstruct.Injection) error {
+		if s, ok := val.(string); ok && s != "" {
+			return nil
+		}
+		return fmt.Errorf("not a widget ID")
+	})
+
+	spec := map[string]any{"w": []any{"`$TYPE`", "voxgigstruct_test.widget"}}
+	if _, err := voxgigstruct.Validate(map[string]any{"w": "W-1"}, spec); err != nil {
+		t.Fatalf("expected a custom type validator to accept a matching value, got %v", err)
+	}
+	if _, err := voxgigstruct.Validate(map[string]any{"w": ""}, spec); err == nil {
+		t.Fatalf("expected a custom type validator to reject a non-matching value")
+	}
+}
@@ -61,7 +61,10 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/voxgig/struct/expr"
 )
 
 // String constants are explicitly defined.
@@ -76,8 +79,35 @@ const (
 	// Special keys.
 	S_DKEY  = "`$KEY`"
 	S_DMETA = "`$META`"
-	S_DTOP  = "$TOP"
-	S_DERRS = "$ERRS"
+	S_DTOP    = "$TOP"
+	S_DERRS   = "$ERRS"
+	S_DREPORT = "$REPORT"
+
+	// Taint-tracking config, threaded through the store map the same way
+	// as S_DERRS/S_DREPORT so it survives into the nested InjectDescend
+	// calls inside Transform_EACH/Transform_PACK.
+	S_DLABELS = "$LABELS"
+	S_DPOLICY = "$POLICY"
+	S_DGUARDS = "$GUARDS"
+
+	// Recursion safeguards, threaded through the store map the same way
+	// as S_DLABELS/S_DPOLICY/S_DGUARDS.
+	S_DMAXDEPTH     = "$MAXDEPTH"
+	S_DDETECTCYCLES = "$DETECTCYCLES"
+
+	// DefaultMaxDepth is the injection depth at which InjectDescend gives
+	// up and reports an error rather than recursing further, unless
+	// overridden via $MAXDEPTH in the store.
+	DefaultMaxDepth = 1024
+
+	// Prefix marking a backtick reference as an expr.Program rather than a
+	// plain path, e.g. "`= a + b * 2`".
+	S_EXPR = "= "
+
+	// Explicit, unambiguous alternative to S_EXPR, e.g.
+	// "`=expr:price * (1 - discount)`" - preferred when a plain-path
+	// reference might itself start with "= ".
+	S_EXPR_PREFIX = "=expr:"
 
 	// General strings.
 	S_array    = "array"
@@ -139,10 +169,51 @@ type Injection struct {
 	Path    []string       // Path to current node.
 	Nodes   []any          // Stack of ancestor nodes.
 	Handler Injector       // Custom handler for injections.
-	Errs    *ListRef[any]  // Error collector.
+	Errs    *ListRef[any]  // Error collector; holds *ValidationError (addErr also accepts a plain string for compatibility).
+	Report  *ValidationReport // Structured, path-aware error collector.
 	Meta    map[string]any // Custom meta data.
 	Base    string         // Base key for data in store, if any.
 	Modify  Modify         // Modify injection output.
+	Labels  map[string]string // Security label by source path, for taint tracking.
+	Policy  TaintPolicy       // Action (e.g. "deny") by label, for taint tracking.
+	Guards  []string          // Output-path globs the Policy is enforced against; nil means everywhere.
+
+	MaxDepth     int                   // Injection depth limit; 0 means DefaultMaxDepth.
+	DetectCycles bool                  // Track visited nodes and error out on a repeat.
+	Visited      map[uintptr]struct{}  // Pointers of nodes currently on the descent stack, if DetectCycles.
+}
+
+// addErr records a validation failure at the current traversal path, as a
+// *ValidationError, in both Errs (so ValidateCollect's joined-message
+// compatibility shim can still read e.Message) and the structured Report,
+// if one is present. e is either a *ValidationError built by a caller
+// like _invalidTypeMsg, or a plain string for an ad-hoc message, which is
+// wrapped in a minimal ValidationError carrying just that message.
+func (state *Injection) addErr(e any) {
+	var verr *ValidationError
+	switch v := e.(type) {
+	case *ValidationError:
+		verr = v
+	case string:
+		verr = &ValidationError{Message: v}
+	default:
+		verr = &ValidationError{Message: fmt.Sprint(v)}
+	}
+
+	if verr.Path == nil {
+		verr.Path = pathAsAny(state.Path)
+	}
+	if verr.Pointer == S_MT {
+		verr.Pointer = jsonPointer(verr.Path)
+	}
+	if verr.Severity == S_MT {
+		verr.Severity = SeverityError
+	}
+
+	state.Errs.Append(verr)
+	if state.Report != nil {
+		state.Report.Add(verr)
+	}
 }
 
 // Apply a custom modification to injections.
@@ -376,9 +447,16 @@ func KeysOf(val any) []string {
 }
 
 
-// Value of property with name key in node val is defined.
+// Value of property with name key in node val is defined. A *Path or
+// []string key is treated as a full path into val rather than a single
+// top-level key - see CompilePath.
 func HasKey(val any, key any) bool {
-	return nil != GetProp(val, key)
+	switch key.(type) {
+	case *Path, []string:
+		return nil != GetPathState(key, val, nil, nil)
+	default:
+		return nil != GetProp(val, key)
+	}
 }
 
 
@@ -659,6 +737,13 @@ func CloneFlags(val any, flags map[string]bool) any {
 // If the value is undefined, remove the list element at index key, and shift the
 // remaining elements down.  These rules avoid "holes" in the list.
 func SetProp(parent any, key any, newval any) any {
+	// A *Path sets deep inside parent, creating intermediate maps as
+	// needed, rather than a single top-level key - see CompilePath.
+	if p, ok := key.(*Path); ok {
+		setAtPath(parent, p.Parts, newval)
+		return parent
+	}
+
 	if !IsKey(key) {
 		return parent
 	}
@@ -762,6 +847,11 @@ func Walk(
 	return WalkDescend(val, apply, nil, nil, nil)
 }
 
+// WalkDescend is implemented in terms of the iterative engine in
+// walkiter.go (walkDescendIter with a nil context), so the same traversal
+// logic backs both the plain Walk/WalkDescend entry points and the
+// context-aware WalkContext, without growing the goroutine stack on deeply
+// nested input.
 func WalkDescend(
 	val any,
 	apply WalkApply,
@@ -769,26 +859,7 @@ func WalkDescend(
 	parent any,
 	path []string,
 ) any {
-
-	if IsNode(val) {
-		for _, kv := range Items(val) {
-			ckey := kv[0]
-			child := kv[1]
-			ckeyStr := StrKey(ckey)
-			newChild := WalkDescend(child, apply, &ckeyStr, val, append(path, ckeyStr))
-			val = SetProp(val, ckey, newChild)
-		}
-
-		if nil != parent && nil != key {
-			SetProp(parent, *key, val)
-		}
-	}
-
-	// Nodes are applied *after* their children.
-	// For the root node, key and parent will be undefined.
-	val = apply(key, val, parent, path)
-
-	return val
+	return walkDescendIter(nil, val, apply, key, parent, path)
 }
 
 // Merge a list of values into each other. Later values have
@@ -897,6 +968,17 @@ func Merge(val any) any {
 // resolved against the `current` argument, if defined.  Integer path
 // parts are used as array indexes.  The state argument allows for
 // custom handling when called from `inject` or `transform`.
+//
+// A string path that starts with "/", or that is "$" or starts with "$."
+// or "$[", is instead treated as a root-anchored query - RFC 6901 JSON
+// Pointer or JSONPath respectively - and resolved via GetPathQuery,
+// returning its first match (see GetPathJSONPointer/FindAll for the
+// pointer/multi-match forms). Other "$"-prefixed paths, such as "$TOP.a",
+// stay ordinary dotted paths.
+//
+// A string path is split and validated on every call unless it's already
+// a *Path from CompilePath, or has recently been seen by CompileCachedPath
+// (see path.go) - pass a *Path directly in a hot loop to skip both.
 func GetPath(path any, store any) any {
 	return GetPathState(path, store, nil, nil)
 }
@@ -907,30 +989,50 @@ func GetPathState(
 	current any,
 	state *Injection,
 ) any {
-	var parts []string
+	var cpath *Path
 
 	val := store
 	root := store
 
-	// Operate on a string array.
+	// Operate on a compiled Path, building one on demand if path isn't
+	// already one - see CompilePath/CompileCachedPath in path.go.
 	switch pp := path.(type) {
+	case *Path:
+		cpath = pp
+
 	case []string:
-		parts = pp
+		cpath = compilePathParts(pp)
 
 	case string:
 		if pp == "" {
-			parts = []string{S_MT}
+			cpath = compilePathParts([]string{S_MT})
+		} else if isDialectPath(pp) {
+			// RFC 6901 JSON Pointer ("/a/b/0") or JSONPath ("$.a.b[0]") -
+			// both are root-anchored, so they bypass the dotted/relative
+			// path resolution below and go straight through GetPathQuery.
+			val = resolveDialectPath(pp, store)
+			if nil != state && state.Handler != nil {
+				ref := Pathify(path)
+				val = state.Handler(state, val, current, &ref, store)
+			}
+			return val
 		} else {
-			parts = strings.Split(pp, S_DT)
+			compiled, err := CompileCachedPath(pp)
+			if err != nil {
+				return nil
+			}
+			cpath = compiled
 		}
 	default:
 		if IsList(path) {
-			parts = _resolveStrings(pp.([]any))
+			cpath = compilePathParts(_resolveStrings(pp.([]any)))
 		} else {
 			return nil
 		}
 	}
 
+	parts := cpath.Parts
+
 	var base *string = nil
 	if nil != state {
 		base = &state.Base
@@ -951,28 +1053,23 @@ func GetPathState(
 			root = current
 		}
 
-		var part *string
-		if pI < len(parts) {
-			part = &parts[pI]
-		}
-
-		first := GetProp(root, *part)
+		first := getPropFast(root, cpath, pI)
 
 		// At top level, check state.base, if provided
 		val = first
 		if nil == first && 0 == pI {
-			val = GetProp(GetProp(root, base), *part)
+			val = GetProp(GetProp(root, base), parts[pI])
 		}
 
 		// Move along the path, trying to descend into the store.
 		pI++
 		for nil != val && pI < len(parts) {
-			val = GetProp(val, parts[pI])
+			val = getPropFast(val, cpath, pI)
 			pI++
 		}
 	}
 
-  
+
 	if nil != state && state.Handler != nil {
 		ref := Pathify(path)
 		val = state.Handler(state, val, current, &ref, store)
@@ -981,6 +1078,175 @@ func GetPathState(
 	return val
 }
 
+// exprCache holds compiled expr.Programs keyed by source text, so that a
+// `= ...` injection appearing on every item of a list is only parsed once.
+var exprCache sync.Map // map[string]*expr.Program
+
+// Expr compiles an expression string (the part after the "= " prefix of a
+// `` `= a + b * 2` `` injection) into a reusable, cached expr.Program.
+func Expr(src string) (*expr.Program, error) {
+	if cached, ok := exprCache.Load(src); ok {
+		return cached.(*expr.Program), nil
+	}
+
+	prog, err := expr.Compile(src)
+	if err != nil {
+		return nil, err
+	}
+
+	exprCache.Store(src, prog)
+	return prog, nil
+}
+
+// exprFuncRegistry holds process-global builtin functions usable by name
+// from every `` `=expr:...` ``/`` `= ...` `` injection expression,
+// registered via RegisterExprFunc. Same-named functions found directly on
+// the store (see exprFuncs) take precedence over a registry entry.
+var exprFuncRegistry sync.Map // map[string]func(args ...any) (any, error)
+
+// RegisterExprFunc registers a builtin function, callable by name from
+// expression-injection strings (e.g. `` `=expr:len(tags)` ``).
+// Registration is process-global and takes effect immediately; a later
+// call with the same name replaces the earlier one.
+func RegisterExprFunc(name string, fn func(args ...any) (any, error)) {
+	exprFuncRegistry.Store(name, fn)
+}
+
+func init() {
+	RegisterExprFunc("len", exprFuncLen)
+	RegisterExprFunc("lower", exprFuncLower)
+	RegisterExprFunc("upper", exprFuncUpper)
+	RegisterExprFunc("has", exprFuncHas)
+	RegisterExprFunc("default", exprFuncDefault)
+}
+
+// exprFuncLen returns the length of a string, list, or map, following the
+// same "number" type Typify would report.
+func exprFuncLen(args ...any) (any, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("len: expected 1 argument, got %d", len(args))
+	}
+	v := reflect.ValueOf(args[0])
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), nil
+	default:
+		return nil, fmt.Errorf("len: unsupported type %T", args[0])
+	}
+}
+
+func exprFuncLower(args ...any) (any, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("lower: expected 1 argument, got %d", len(args))
+	}
+	s, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("lower: expected a string, got %T", args[0])
+	}
+	return strings.ToLower(s), nil
+}
+
+func exprFuncUpper(args ...any) (any, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("upper: expected 1 argument, got %d", len(args))
+	}
+	s, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("upper: expected a string, got %T", args[0])
+	}
+	return strings.ToUpper(s), nil
+}
+
+// exprFuncHas reports whether key is a defined property of val, per HasKey.
+func exprFuncHas(args ...any) (any, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("has: expected 2 arguments, got %d", len(args))
+	}
+	return HasKey(args[0], args[1]), nil
+}
+
+// exprFuncDefault returns val unless it's nil, in which case it returns
+// fallback - the expression-language equivalent of a `?:`-style default.
+func exprFuncDefault(args ...any) (any, error) {
+	if len(args) != 2 {
+		return nil, fmt.Errorf("default: expected 2 arguments, got %d", len(args))
+	}
+	if args[0] == nil {
+		return args[1], nil
+	}
+	return args[0], nil
+}
+
+// exprFuncs builds the expr function table: every registered builtin (see
+// RegisterExprFunc), overlaid with any func(args ...any) any found as a
+// top-level key of the store (e.g. the store passed to Inject). Under
+// TransformModify, extra's non-"$" entries sit under the store's "$TOP"
+// data and its "$"-prefixed names are not valid expression identifiers, so
+// neither is callable from an expression.
+func exprFuncs(store any) map[string]expr.Func {
+	funcs := map[string]expr.Func{}
+
+	exprFuncRegistry.Range(func(k, v any) bool {
+		funcs[k.(string)] = expr.Func(v.(func(args ...any) (any, error)))
+		return true
+	})
+
+	storeMap, ok := store.(map[string]any)
+	if !ok {
+		return funcs
+	}
+
+	for k, v := range storeMap {
+		fn, ok := v.(func(args ...any) any)
+		if !ok {
+			continue
+		}
+		funcs[k] = func(args ...any) (out any, err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					err = fmt.Errorf("expr: function call failed: %v", r)
+				}
+			}()
+			return fn(args...), nil
+		}
+	}
+
+	return funcs
+}
+
+// _injectExpr evaluates a `` `= ...` `` expression against the current
+// Injection state, surfacing evaluation errors through the same path-aware
+// error mechanism as the rest of validate/inject.
+func _injectExpr(src string, store any, current any, state *Injection) any {
+	prog, err := Expr(src)
+	if err != nil {
+		if state != nil {
+			state.addErr(fmt.Sprintf("invalid expression `%s`: %s", src, err.Error()))
+		}
+		return nil
+	}
+
+	var path []string
+	if state != nil {
+		path = state.Path
+	}
+
+	out, err := prog.Eval(expr.EvalContext{
+		Current: current,
+		Store:   store,
+		Funcs:   exprFuncs(store),
+		Path:    path,
+	})
+	if err != nil {
+		if state != nil {
+			state.addErr(fmt.Sprintf("expression `%s` failed: %s", src, err.Error()))
+		}
+		return nil
+	}
+
+	return out
+}
+
 // Inject store values into a string. Not a public utility - used by
 // `inject`.  Inject are marked with `path` where path is resolved
 // with getpath against the store or current (if defined)
@@ -1012,6 +1278,20 @@ func _injectStr(
 		}
 		pathref := matches[1]
 
+		// An expression, not a plain path: `=expr:price * (1 - discount)`
+		// or the shorthand `= a + b * 2`.
+		if strings.HasPrefix(pathref, S_EXPR_PREFIX) {
+			return _injectExpr(strings.TrimPrefix(pathref, S_EXPR_PREFIX), store, current, state)
+		}
+		if strings.HasPrefix(pathref, S_EXPR) {
+			return _injectExpr(strings.TrimPrefix(pathref, S_EXPR), store, current, state)
+		}
+
+		// A CUE-style constraint, not a plain path: `int & >=0 & <=100`.
+		if looksLikeConstraint(pathref) {
+			return validateConstraint(pathref)(state, nil, current, &pathref, store)
+		}
+
 		// Special escapes inside injection.
 		if len(pathref) > 3 {
 			pathref = strings.ReplaceAll(pathref, "$BT", S_BT)
@@ -1020,6 +1300,7 @@ func _injectStr(
 
 		// Get the extracted path reference.
 		out := GetPathState(pathref, store, current, state)
+		recordTaint(state, taintLabelsFor(state, pathref))
 
 		return out
 	}
@@ -1038,6 +1319,7 @@ func _injectStr(
 			state.Full = false
 		}
 		found := GetPathState(ref, store, current, state)
+		recordTaint(state, taintLabelsFor(state, ref))
 
 		if nil == found {
 			return S_MT
@@ -1104,8 +1386,31 @@ func InjectDescend(
 			Base:    S_DTOP,
 			Modify:  modify,
 			Errs:    GetProp(store, S_DERRS, ListRefCreate[any]()).(*ListRef[any]),
+			Report:  GetProp(store, S_DREPORT, NewValidationReport()).(*ValidationReport),
 			Meta:    make(map[string]any),
 		}
+
+		state.Labels, _ = GetProp(store, S_DLABELS).(map[string]string)
+		state.Policy, _ = GetProp(store, S_DPOLICY).(TaintPolicy)
+		state.Guards, _ = GetProp(store, S_DGUARDS).([]string)
+
+		state.MaxDepth = DefaultMaxDepth
+		if maxDepth, ok := GetProp(store, S_DMAXDEPTH).(int); ok && maxDepth > 0 {
+			state.MaxDepth = maxDepth
+		}
+		if state.DetectCycles, _ = GetProp(store, S_DDETECTCYCLES).(bool); state.DetectCycles {
+			state.Visited = map[uintptr]struct{}{}
+		}
+	}
+
+	// Give up rather than blow the stack on a pathologically deep spec or
+	// a $MERGE/$COPY cycle that keeps re-entering the same ancestor.
+	if state.MaxDepth <= 0 {
+		state.MaxDepth = DefaultMaxDepth
+	}
+	if state.MaxDepth < len(state.Path) {
+		state.addErr("Maximum injection depth exceeded at " + Pathify(state.Path, 1))
+		return GetProp(state.Parent, S_DTOP)
 	}
 
 	// Resolve current node in store for local paths.
@@ -1115,13 +1420,27 @@ func InjectDescend(
 		}
 	} else {
 		if len(state.Path) > 1 {
-			parentKey := state.Path[len(state.Path)-2]
+			// A "?" optional-field suffix is spec-only - it never appears in
+			// the data being validated/injected - so strip it before using
+			// the path segment to navigate into current.
+			parentKey := strings.TrimSuffix(state.Path[len(state.Path)-2], "?")
 			current = GetProp(current, parentKey)
 		}
 	}
 
 	// Descend into node
 	if IsNode(val) {
+		if state.DetectCycles {
+			if ptr, ok := _nodePointer(val); ok {
+				if _, seen := state.Visited[ptr]; seen {
+					state.addErr("Cycle detected at " + Pathify(state.Path, 1))
+					return GetProp(state.Parent, S_DTOP)
+				}
+				state.Visited[ptr] = struct{}{}
+				defer delete(state.Visited, ptr)
+			}
+		}
+
 		childkeys := KeysOf(val)
 
 		// Keys are sorted alphanumerically to ensure determinism.
@@ -1170,7 +1489,15 @@ func InjectDescend(
 				Base:    state.Base,
 				Modify:  state.Modify,
 				Errs:    state.Errs,
+				Report:  state.Report,
 				Meta:    state.Meta,
+				Labels:  state.Labels,
+				Policy:  state.Policy,
+				Guards:  state.Guards,
+
+				MaxDepth:     state.MaxDepth,
+				DetectCycles: state.DetectCycles,
+				Visited:      state.Visited,
 			}
 
 			// Peform the key:pre mode injection on the child key.
@@ -1307,6 +1634,7 @@ var Transform_COPY Injector = func(
 	if !strings.HasPrefix(string(state.Mode), "key") {
 		out = GetProp(current, state.Key)
     _setParentProp("CP", state, out)
+		recordTaint(state, taintLabelsFor(state, Pathify(state.Path, 1)))
 	}
 
 	return out
@@ -1407,6 +1735,11 @@ var Transform_MERGE Injector = func(
 
 		Merge(mergeList)
 
+		// $MERGE's source-path arguments are themselves backtick references
+		// ('`source-path`'), so they already pass through _injectStr's own
+		// taint hook when resolved as ordinary children during key:pre/val -
+		// nothing further to join here.
+
 		return state.Key
 	}
 
@@ -1444,6 +1777,10 @@ var Transform_EACH Injector = func(
   // var src any = nil
   srcstore := GetProp(store, state.Base, store)
   src := GetPathState(srcpath, srcstore, current, nil)
+
+	if srcpathStr, ok := srcpath.(string); ok {
+		recordTaint(state, taintLabelsFor(state, srcpathStr))
+	}
   
 	// Create parallel data structures:
 	// source entries :: child templates
@@ -1561,6 +1898,10 @@ var Transform_PACK Injector = func(
   // FIX: this should not need state
   src := GetPathState(srcpath, store, current, state)
 
+	if srcpathStr, ok := srcpath.(string); ok {
+		recordTaint(state, taintLabelsFor(state, srcpathStr))
+	}
+
 	// Convert map to list if needed
 	var srclist []any
 
@@ -1693,6 +2034,13 @@ func TransformModify(
 		"$MERGE":  Transform_MERGE,
 		"$EACH":   Transform_EACH,
 		"$PACK":   Transform_PACK,
+
+		// Collection-oriented transforms.
+		"$FILTER":  Transform_FILTER,
+		"$SORT":    Transform_SORT,
+		"$GROUP":   Transform_GROUP,
+		"$REDUCE":  Transform_REDUCE,
+		"$FLATTEN": Transform_FLATTEN,
 	}
 
 	// Add any extra transforms
@@ -1705,6 +2053,26 @@ func TransformModify(
 	return out
 }
 
+// _scalarOptionalAbsent reports whether a scalar validator should silently
+// skip rather than error on a missing value: either the field's own key
+// carries the "?" optional suffix, or current is nil because an ancestor
+// key (the immediate parent segment in state.Path) was itself "?"-marked
+// and absent from the data - in which case every descendant of that
+// ancestor is absent too, not individually missing.
+func _scalarOptionalAbsent(state *Injection, current any) bool {
+	lookupKey := strings.TrimSuffix(state.Key, "?")
+	if lookupKey != state.Key {
+		return true
+	}
+
+	if nil == current && 1 < len(state.Path) &&
+		strings.HasSuffix(state.Path[len(state.Path)-2], "?") {
+		return true
+	}
+
+	return false
+}
+
 var validate_STRING Injector = func(
 	state *Injection,
 	_val any,
@@ -1712,18 +2080,22 @@ var validate_STRING Injector = func(
 	ref *string,
 	store any,
 ) any {
-	out := GetProp(current, state.Key)
+	lookupKey := strings.TrimSuffix(state.Key, "?")
+	out := GetProp(current, lookupKey)
+	if nil == out && _scalarOptionalAbsent(state, current) {
+		return nil
+	}
 
 	t := Typify(out)
 	if S_string != t {
 		msg := _invalidTypeMsg(state.Path, S_string, t, out)
-		state.Errs.Append(msg)
+		state.addErr(msg)
 		return nil
 	}
 
 	if S_MT == out.(string) {
 		msg := "Empty string at " + Pathify(state.Path, 0)
-		state.Errs.Append(msg)
+		state.addErr(msg)
 		return nil
 	}
 
@@ -1737,12 +2109,16 @@ var validate_NUMBER Injector = func(
 	ref *string,
 	store any,
 ) any {
-	out := GetProp(current, state.Key)
+	lookupKey := strings.TrimSuffix(state.Key, "?")
+	out := GetProp(current, lookupKey)
+	if nil == out && _scalarOptionalAbsent(state, current) {
+		return nil
+	}
 
 	t := Typify(out)
 	if S_number != t {
 		msg := _invalidTypeMsg(state.Path, S_number, t, out)
-		state.Errs.Append(msg)
+		state.addErr(msg)
 		return nil
 	}
 
@@ -1756,12 +2132,16 @@ var validate_BOOLEAN Injector = func(
 	ref *string,
 	store any,
 ) any {
-	out := GetProp(current, state.Key)
+	lookupKey := strings.TrimSuffix(state.Key, "?")
+	out := GetProp(current, lookupKey)
+	if nil == out && _scalarOptionalAbsent(state, current) {
+		return nil
+	}
 
 	t := Typify(out)
 	if S_boolean != t {
 		msg := _invalidTypeMsg(state.Path, S_boolean, t, out)
-		state.Errs.Append(msg)
+		state.addErr(msg)
 		return nil
 	}
 
@@ -1775,13 +2155,17 @@ var validate_OBJECT Injector = func(
 	ref *string,
 	store any,
 ) any {
-	out := GetProp(current, state.Key)
+	lookupKey := strings.TrimSuffix(state.Key, "?")
+	out := GetProp(current, lookupKey)
+	if nil == out && _scalarOptionalAbsent(state, current) {
+		return nil
+	}
 
 	t := Typify(out)
 
 	if S_object != t {
 		msg := _invalidTypeMsg(state.Path, S_object, t, out)
-		state.Errs.Append(msg)
+		state.addErr(msg)
 
     return nil
 	}
@@ -1796,12 +2180,16 @@ var validate_ARRAY Injector = func(
 	ref *string,
 	store any,
 ) any {
-	out := GetProp(current, state.Key)
+	lookupKey := strings.TrimSuffix(state.Key, "?")
+	out := GetProp(current, lookupKey)
+	if nil == out && _scalarOptionalAbsent(state, current) {
+		return nil
+	}
 
 	t := Typify(out)
 	if S_array != t {
 		msg := _invalidTypeMsg(state.Path, S_array, t, out)
-		state.Errs.Append(msg)
+		state.addErr(msg)
 		return nil
 	}
 
@@ -1815,12 +2203,16 @@ var validate_FUNCTION Injector = func(
 	ref *string,
 	store any,
 ) any {
-	out := GetProp(current, state.Key)
+	lookupKey := strings.TrimSuffix(state.Key, "?")
+	out := GetProp(current, lookupKey)
+	if nil == out && _scalarOptionalAbsent(state, current) {
+		return nil
+	}
 
 	t := Typify(out)
 	if S_function != t {
 		msg := _invalidTypeMsg(state.Path, S_function, t, out)
-		state.Errs.Append(msg)
+		state.addErr(msg)
 		return nil
 	}
 
@@ -1834,7 +2226,7 @@ var validate_ANY Injector = func(
 	ref *string,
 	store any,
 ) any {
-	return GetProp(current, state.Key)
+	return GetProp(current, strings.TrimSuffix(state.Key, "?"))
 }
 
 var validate_CHILD Injector = func(
@@ -1855,7 +2247,7 @@ var validate_CHILD Injector = func(
 			tval = map[string]any{}
 
 		} else if !IsMap(tval) {
-			state.Errs.Append(
+			state.addErr(
 				_invalidTypeMsg(
 					state.Path[:len(state.Path)-1],
 					S_object,
@@ -1882,7 +2274,7 @@ var validate_CHILD Injector = func(
 
 		// We expect 'parent' to be a slice of any, like ["`$CHILD`", childTemplate].
 		if !IsList(state.Parent) {
-			state.Errs.Append("Invalid $CHILD as value")
+			state.addErr("Invalid $CHILD as value")
 			return nil
 		}
 
@@ -1898,7 +2290,7 @@ var validate_CHILD Injector = func(
 
 		// If current is not a list => error
 		if !IsList(current) {
-			state.Errs.Append(
+			state.addErr(
 				_invalidTypeMsg(
 					state.Path[:len(state.Path)-1],
 					S_array,
@@ -1951,7 +2343,7 @@ func init_validate_ONE() {
 		if state.Mode == S_MVAL {
 			// Validate that parent is a list and we're at the first element
 			if !IsList(state.Parent) || state.KeyI != 0 {
-				state.Errs.Append("The $ONE validator at field " +
+				state.addErr("The $ONE validator at field " +
 					Pathify(state.Path, 1, 1) +
 					" must be the first element of an array.")
 				return nil
@@ -1984,7 +2376,7 @@ func init_validate_ONE() {
 			
 			// Ensure we have at least one alternative
 			if len(tvals) == 0 {
-				state.Errs.Append("The $ONE validator at field " +
+				state.addErr("The $ONE validator at field " +
 					Pathify(state.Path, 1, 1) +
 					" must have at least one argument.")
 				return nil
@@ -2040,7 +2432,7 @@ func init_validate_ONE() {
 				current,
 				"V0210",
 			)
-			state.Errs.Append(msg)
+			state.addErr(msg)
 		}
 
 		return nil
@@ -2059,7 +2451,7 @@ func init_validate_EXACT() {
 		if state.Mode == S_MVAL {
 			// Validate that parent is a list and we're at the first element
 			if !IsList(state.Parent) || state.KeyI != 0 {
-				state.Errs.Append("The $EXACT validator at field " +
+				state.addErr("The $EXACT validator at field " +
 					Pathify(state.Path, 1, 1) +
 					" must be the first element of an array.")
 				return nil
@@ -2091,7 +2483,7 @@ func init_validate_EXACT() {
 
 			// Ensure we have at least one alternative
 			if len(tvals) == 0 {
-				state.Errs.Append("The $EXACT validator at field " +
+				state.addErr("The $EXACT validator at field " +
 					Pathify(state.Path, 1, 1) +
 					" must have at least one argument.")
 				return nil
@@ -2160,7 +2552,7 @@ func init_validate_EXACT() {
 				current,
 				"V0110",
 			)
-			state.Errs.Append(msg)
+			state.addErr(msg)
 		} else {
 			SetProp(state.Parent, state.Key, nil)
 		}
@@ -2181,9 +2573,34 @@ func validation(
 		return
 	}
 
+	// A spec key of the form "name?" marks the field optional (CUE-style);
+	// resolve data against the name with the suffix stripped.
+	lookupKey := key
+	optional := false
+	if ks, ok := key.(string); ok && strings.HasSuffix(ks, "?") {
+		optional = true
+		lookupKey = strings.TrimSuffix(ks, "?")
+	}
+
 	// Current val to verify.
-	cval := GetProp(current, key)
+	cval := GetProp(current, lookupKey)
 	if cval == nil {
+		// An absent optional field may still carry a literal default value
+		// sitting untouched in the output (e.g. "age?": 18 from a struct
+		// tag's "default=18") - normalize away its "?" placeholder key so
+		// callers see the plain field name, same as the present-data case
+		// below.
+		if optional {
+			if pm, ok := parent.(map[string]any); ok {
+				if ks, ok2 := key.(string); ok2 {
+					lks := strings.TrimSuffix(ks, "?")
+					if ks != lks && HasKey(pm, ks) {
+						pm[lks] = pm[ks]
+						delete(pm, ks)
+					}
+				}
+			}
+		}
 		return
 	}
 
@@ -2201,7 +2618,7 @@ func validation(
 
 	// Type mismatch.
 	if ptype != ctype && pval != nil {
-		state.Errs.Append(_invalidTypeMsg(state.Path, ptype, ctype, cval))
+		state.addErr(_invalidTypeMsg(state.Path, ptype, ctype, cval))
 		return
 	}
 
@@ -2213,7 +2630,7 @@ func validation(
 			} else {
 				errType = ptype
 			}
-			state.Errs.Append(_invalidTypeMsg(state.Path, errType, ctype, cval))
+			state.addErr(_invalidTypeMsg(state.Path, errType, ctype, cval))
 			return
 		}
 
@@ -2222,18 +2639,46 @@ func validation(
 
 		// Empty spec object {} means object can be open (any keys).
 		if len(pkeys) > 0 && GetProp(pval, "`$OPEN`") != true {
+			// A "?" optional-field suffix only ever appears on the spec
+			// side; data keys never carry it, so compare against the
+			// trimmed names.
+			allowed := map[string]bool{}
+			for _, pkey := range pkeys {
+				allowed[strings.TrimSuffix(pkey, "?")] = true
+			}
+
 			badkeys := []string{}
 			for _, ckey := range ckeys {
-				if !HasKey(val, ckey) {
+				if !allowed[ckey] && !HasKey(val, ckey) {
 					badkeys = append(badkeys, ckey)
 				}
 			}
 
 			// Closed object, so reject extra keys not in shape.
 			if len(badkeys) > 0 {
-				state.Errs.Append("Unexpected keys at field " + Pathify(state.Path, 1) +
+				state.addErr("Unexpected keys at field " + Pathify(state.Path, 1) +
 					": " + strings.Join(badkeys, ", "))
 			}
+
+			// Fields are required unless marked optional with a "?" suffix.
+			missing := []string{}
+			for _, pkey := range pkeys {
+				if pkey == "`$OPEN`" {
+					continue
+				}
+				fieldKey := strings.TrimSuffix(pkey, "?")
+				// A bare pkey already holding a value in pval (and data
+				// doesn't) means an earlier "?"-marked default field was
+				// already normalized down to its plain name - not a truly
+				// required field that's missing.
+				if fieldKey == pkey && !HasKey(cval, fieldKey) && !HasKey(pval, fieldKey) {
+					missing = append(missing, fieldKey)
+				}
+			}
+			if len(missing) > 0 {
+				state.addErr("Missing required keys at field " + Pathify(state.Path, 1) +
+					": " + strings.Join(missing, ", "))
+			}
 		} else {
 			// Object is open, so merge in extra keys.
 			Merge([]any{pval, cval})
@@ -2243,11 +2688,20 @@ func validation(
 		}
 	} else if IsList(cval) {
 		if !IsList(val) {
-			state.Errs.Append(_invalidTypeMsg(state.Path, ptype, ctype, cval))
+			state.addErr(_invalidTypeMsg(state.Path, ptype, ctype, cval))
 		}
 	} else {
 		// Spec value was a default, copy over data
-		SetProp(parent, key, cval)
+		SetProp(parent, lookupKey, cval)
+
+		// Normalize away the "?" placeholder key left over from the spec.
+		if optional {
+			if pm, ok := parent.(map[string]any); ok {
+				if ks, ok2 := key.(string); ok2 {
+					delete(pm, ks)
+				}
+			}
+		}
 	}
 
 	return
@@ -2255,17 +2709,62 @@ func validation(
 
 func Validate(
 	data any, // The input data
-	spec any, // The shape specification
+	spec any, // The shape specification, or a Go struct type/instance (see ValidateStruct)
 ) (any, error) {
+	if derived, ok := structSpecForSpec(spec); ok {
+		spec = derived
+	}
 	return ValidateCollect(data, spec, nil, nil)
 }
 
 
+// ValidateReport validates data against spec, like Validate, but also
+// returns the structured ValidationReport collected during validation, for
+// callers that want field-level diagnostics instead of a joined string.
+func ValidateReport(
+	data any,
+	spec any,
+) (any, *ValidationReport, error) {
+	report := NewValidationReport()
+	out, err := validateCollect(data, spec, nil, nil, report)
+	return out, report, err
+}
+
+
 func ValidateCollect(
 	data any,
 	spec any,
 	extra map[string]any,
 	collecterrs *ListRef[any],
+) (any, error) {
+	return validateCollect(data, spec, extra, collecterrs, NewValidationReport())
+}
+
+
+// ValidateCollectStructured validates data against spec like ValidateCollect,
+// but collecterrs (when non-nil) accumulates the structured *ValidationError
+// values directly, instead of the joined-string compatibility shim.
+func ValidateCollectStructured(
+	data any,
+	spec any,
+	extra map[string]any,
+	collecterrs *ListRef[*ValidationError],
+) (any, error) {
+	report := NewValidationReport()
+	out, err := validateCollect(data, spec, extra, nil, report)
+	if collecterrs != nil {
+		collecterrs.List = append(collecterrs.List, report.Errors...)
+	}
+	return out, err
+}
+
+
+func validateCollect(
+	data any,
+	spec any,
+	extra map[string]any,
+	collecterrs *ListRef[any],
+	report *ValidationReport,
 ) (any, error) {
 	// Use the provided error collection or create a new one
 	errs := collecterrs
@@ -2273,7 +2772,7 @@ func ValidateCollect(
 		errs = ListRefCreate[any]()
 	}
 
-  
+
 	// Initialize validate_ONE if not already initialized.
 	// This avoids a circular reference error, validate_ONE calls ValidateCollect.
 	if validate_ONE == nil {
@@ -2310,19 +2809,36 @@ func ValidateCollect(
 		"$CHILD":    validate_CHILD,
 		"$ONE":      validate_ONE,
 		"$EXACT":    validate_EXACT,
+
+		"$REGEX":  validate_REGEX,
+		"$ENUM":   validate_ENUM,
+		"$RANGE":  validate_RANGE,
+		"$LENGTH": validate_LENGTH,
+		"$FORMAT": validate_FORMAT,
+		"$TYPE":   validate_TYPE,
 	}
 
-	// Add any extra validation commands
+	// Add any extra validation commands. extra["$FORMAT"] is special-cased:
+	// an ErrorFormatter there controls how the final joined error below is
+	// rendered, rather than overriding the built-in $FORMAT validator.
+	var formatter ErrorFormatter
 	if extra != nil {
 		for k, fn := range extra {
+			if k == "$FORMAT" {
+				if asFormatter, ok := fn.(ErrorFormatter); ok {
+					formatter = asFormatter
+					continue
+				}
+			}
 			store[k] = fn
 		}
 	}
 
   // A special top level value to collect errors
   store["$ERRS"] = errs
+  store[S_DREPORT] = report
+
 
-  
 	// Run the transformation with validation
 	out := TransformModify(data, spec, store, validation)
 
@@ -2331,16 +2847,25 @@ func ValidateCollect(
 	var err error
 	generr := 0 < len(errs.List) && collecterrs == nil
 	if generr {
-		// Join error messages
-		errmsgs := make([]string, len(errs.List))
-		for i, e := range errs.List {
-			if s, ok := e.(string); ok {
-				errmsgs[i] = s
-			} else {
-				errmsgs[i] = fmt.Sprintf("%v", e)
+		if formatter != nil {
+			structured := ListRefCreate[*ValidationError]()
+			structured.List = report.Errors
+			err = fmt.Errorf("%s", formatter(structured))
+		} else {
+			// Join error messages
+			errmsgs := make([]string, len(errs.List))
+			for i, e := range errs.List {
+				switch v := e.(type) {
+				case *ValidationError:
+					errmsgs[i] = v.Message
+				case string:
+					errmsgs[i] = v
+				default:
+					errmsgs[i] = fmt.Sprintf("%v", e)
+				}
 			}
+			err = fmt.Errorf("Invalid data: %s", strings.Join(errmsgs, " | "))
 		}
-		err = fmt.Errorf("Invalid data: %s", strings.Join(errmsgs, " | "))
 	}
 
 	return out, err
@@ -2380,7 +2905,7 @@ func _join(vals []any, sep string) string {
 }
 
 
-func _invalidTypeMsg(path []string, needtype string, vt string, v any, whence ...string) string {
+func _invalidTypeMsg(path []string, needtype string, vt string, v any, whence ...string) *ValidationError {
 	vs := "no value"
 	if v != nil {
 		vs = Stringify(v)
@@ -2397,14 +2922,25 @@ func _invalidTypeMsg(path []string, needtype string, vt string, v any, whence ..
 	}
 
 	// Build the main error message
-	message := "Expected " + fieldPart + needtype + ", but found " + typePart + vs
+	message := "Expected " + fieldPart + needtype + ", but found " + typePart + vs + "."
 
-	// Uncomment to help debug validation errors
-	// if len(whence) > 0 {
-	//    message += " [" + whence[0] + "]"
-	// }
+	code := S_MT
+	if len(whence) > 0 {
+		code = whence[0]
+	}
 
-	return message + "."
+	pathAny := pathAsAny(path)
+	return &ValidationError{
+		Path:     pathAny,
+		Pointer:  jsonPointer(pathAny),
+		Code:     code,
+		Expected: needtype,
+		Got:      vs,
+		GotType:  vt,
+		Value:    v,
+		Message:  message,
+		Severity: SeverityError,
+	}
 }
 
 func _getType(v any) string {
@@ -2414,6 +2950,20 @@ func _getType(v any) string {
 	return reflect.TypeOf(v).String()
 }
 
+// _nodePointer returns the underlying map/slice address of val, used by
+// InjectDescend's cycle guard to recognize when a descent has looped
+// back onto a node already on the current path. false for anything that
+// isn't a map or slice.
+func _nodePointer(val any) (uintptr, bool) {
+	rv := reflect.ValueOf(val)
+	switch rv.Kind() {
+	case reflect.Map, reflect.Slice:
+		return rv.Pointer(), true
+	default:
+		return 0, false
+	}
+}
+
 
 // StrKey converts different types of keys to string representation.
 // String keys are returned as is.
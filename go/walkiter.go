@@ -0,0 +1,234 @@
+package voxgigstruct
+
+import (
+	"context"
+)
+
+// WalkPhase identifies which part of a node's traversal a WalkEvent
+// represents.
+type WalkPhase int
+
+const (
+	WalkEnter WalkPhase = iota // a node, before its children are visited
+	WalkLeaf                   // a scalar, or a node cut off by MaxDepth (SkipFunc-pruned values emit no event)
+	WalkExit                   // a node, after its children have been visited
+)
+
+func (p WalkPhase) String() string {
+	switch p {
+	case WalkEnter:
+		return "enter"
+	case WalkLeaf:
+		return "leaf"
+	case WalkExit:
+		return "exit"
+	default:
+		return "unknown"
+	}
+}
+
+// WalkOrder controls which phases WalkIter emits for node values.
+type WalkOrder int
+
+const (
+	OrderPost WalkOrder = iota // emit WalkExit only (matches Walk's apply-after-children order)
+	OrderPre                   // emit WalkEnter only
+	OrderBoth                  // emit both WalkEnter and WalkExit
+)
+
+// WalkOptions configures WalkIter. The zero value walks every node to
+// unlimited depth in post-order.
+type WalkOptions struct {
+	MaxDepth int                               // 0 means unlimited
+	Order    WalkOrder                         // which phases to emit for nodes
+	SkipFunc func(path []string, val any) bool // return true to prune val (and its children) from the walk
+}
+
+// WalkEvent is one step of a WalkIter traversal.
+type WalkEvent struct {
+	Phase  WalkPhase
+	Key    *string // nil for the root
+	Val    any
+	Parent any
+	Path   []string
+}
+
+// WalkIter streams a depth-first traversal of val as WalkEvents over a
+// channel, so a caller can process very large node trees without building
+// up a per-node apply closure the way Walk does. The channel is closed once
+// traversal completes; a caller that wants to stop early should keep
+// draining until it sees the channel close (e.g. in a goroutine), since an
+// abandoned receive leaves the sending goroutine blocked. Use MaxDepth or
+// SkipFunc to bound the walk up front when only part of the tree is needed.
+//
+// Unlike Walk/WalkDescend, WalkIter never mutates val: it's read-only
+// observation, driven by an explicit stack rather than recursion, so its
+// depth is bounded by heap memory rather than goroutine stack size (the
+// benchmarks in walkiter_test.go measure the same explicit-stack approach,
+// through WalkContext, against recursive descent on deeply nested input).
+func WalkIter(val any, opts ...WalkOptions) <-chan WalkEvent {
+	var o WalkOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+
+	ch := make(chan WalkEvent)
+	go func() {
+		defer close(ch)
+		walkEmit(val, nil, nil, o, func(ev WalkEvent) bool {
+			ch <- ev
+			return true
+		})
+	}()
+	return ch
+}
+
+type walkEmitFrame struct {
+	val    any
+	key    *string
+	parent any
+	path   []string
+	items  [][2]any
+	idx    int
+	isNode bool
+	inited bool
+}
+
+// walkEmit drives the WalkIter traversal with an explicit stack instead of
+// recursion, emitting one event per visited value via emit (which returns
+// false to abort the walk early).
+func walkEmit(root any, key *string, parent any, opts WalkOptions, emit func(WalkEvent) bool) {
+	stack := []*walkEmitFrame{{val: root, key: key, parent: parent, path: []string{}}}
+
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+
+		if !top.inited {
+			top.inited = true
+
+			if opts.SkipFunc != nil && opts.SkipFunc(top.path, top.val) {
+				stack = stack[:len(stack)-1]
+				continue
+			}
+
+			top.isNode = IsNode(top.val) && (opts.MaxDepth <= 0 || len(top.path) < opts.MaxDepth)
+
+			if !top.isNode {
+				if !emit(WalkEvent{Phase: WalkLeaf, Key: top.key, Val: top.val, Parent: top.parent, Path: top.path}) {
+					return
+				}
+				stack = stack[:len(stack)-1]
+				continue
+			}
+
+			if opts.Order == OrderPre || opts.Order == OrderBoth {
+				if !emit(WalkEvent{Phase: WalkEnter, Key: top.key, Val: top.val, Parent: top.parent, Path: top.path}) {
+					return
+				}
+			}
+
+			top.items = Items(top.val)
+		}
+
+		if top.idx < len(top.items) {
+			kv := top.items[top.idx]
+			top.idx++
+			ckeyStr := StrKey(kv[0])
+			childPath := append(append([]string{}, top.path...), ckeyStr)
+			stack = append(stack, &walkEmitFrame{val: kv[1], key: &ckeyStr, parent: top.val, path: childPath})
+			continue
+		}
+
+		if opts.Order == OrderPost || opts.Order == OrderBoth {
+			if !emit(WalkEvent{Phase: WalkExit, Key: top.key, Val: top.val, Parent: top.parent, Path: top.path}) {
+				return
+			}
+		}
+
+		stack = stack[:len(stack)-1]
+	}
+}
+
+// WalkContext is Walk's context-aware sibling: it descends val exactly the
+// way WalkDescend does - applying apply post-order and writing each
+// replacement back in place - but checks ctx.Done() between nodes and
+// aborts cleanly, returning whatever the walk had accumulated so far.
+func WalkContext(ctx context.Context, val any, apply WalkApply) any {
+	return walkDescendIter(ctx, val, apply, nil, nil, nil)
+}
+
+type walkDescendFrame struct {
+	val    any
+	key    *string
+	parent any
+	path   []string
+	items  [][2]any
+	idx    int
+	isNode bool
+	inited bool
+}
+
+// walkDescendIter is the iterative engine behind both WalkDescend and
+// WalkContext: an explicit-stack postorder traversal equivalent to the
+// original recursive WalkDescend (apply runs on a node only after all its
+// children have been applied and written back via SetProp), but without
+// growing the goroutine's call stack - see walkiter_test.go for a benchmark
+// against the recursive form on deeply nested input.
+func walkDescendIter(
+	ctx context.Context,
+	val any,
+	apply WalkApply,
+	key *string,
+	parent any,
+	path []string,
+) any {
+	root := &walkDescendFrame{val: val, key: key, parent: parent, path: path}
+	stack := []*walkDescendFrame{root}
+
+	for len(stack) > 0 {
+		if ctx != nil {
+			select {
+			case <-ctx.Done():
+				return root.val
+			default:
+			}
+		}
+
+		top := stack[len(stack)-1]
+
+		if !top.inited {
+			top.inited = true
+			top.isNode = IsNode(top.val)
+			if top.isNode {
+				top.items = Items(top.val)
+			}
+		}
+
+		if top.idx < len(top.items) {
+			kv := top.items[top.idx]
+			top.idx++
+			ckeyStr := StrKey(kv[0])
+			childPath := append(append([]string{}, top.path...), ckeyStr)
+			stack = append(stack, &walkDescendFrame{val: kv[1], key: &ckeyStr, parent: top.val, path: childPath})
+			continue
+		}
+
+		if top.isNode && nil != top.parent && nil != top.key {
+			SetProp(top.parent, *top.key, top.val)
+		}
+
+		newVal := apply(top.key, top.val, top.parent, top.path)
+
+		stack = stack[:len(stack)-1]
+
+		if len(stack) == 0 {
+			return newVal
+		}
+
+		parentFrame := stack[len(stack)-1]
+		childKey := parentFrame.items[parentFrame.idx-1][0]
+		parentFrame.val = SetProp(parentFrame.val, childKey, newVal)
+	}
+
+	return root.val
+}
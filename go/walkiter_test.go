@@ -0,0 +1,176 @@
+package voxgigstruct_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	voxgigstruct "github.com/voxgig/struct"
+)
+
+func TestWalkIterPostOrder(t *testing.T) {
+	val := map[string]any{"a": float64(1), "b": map[string]any{"c": float64(2)}}
+
+	var phases []string
+	for ev := range voxgigstruct.WalkIter(val) {
+		phases = append(phases, ev.Phase.String())
+	}
+
+	// Post-order (the default): every node's children are visited, then the
+	// node itself as an Exit, with the root last.
+	want := []string{"leaf", "leaf", "exit", "exit"}
+	if len(phases) != len(want) {
+		t.Fatalf("expected %d events, got %+v", len(want), phases)
+	}
+	if phases[len(phases)-1] != "exit" {
+		t.Fatalf("expected root exit last, got %+v", phases)
+	}
+}
+
+func TestWalkIterPreAndBothOrder(t *testing.T) {
+	val := map[string]any{"a": map[string]any{"b": float64(1)}}
+
+	var pre []string
+	for ev := range voxgigstruct.WalkIter(val, voxgigstruct.WalkOptions{Order: voxgigstruct.OrderPre}) {
+		pre = append(pre, ev.Phase.String())
+	}
+	if pre[0] != "enter" {
+		t.Fatalf("expected root enter first under OrderPre, got %+v", pre)
+	}
+
+	var both []string
+	for ev := range voxgigstruct.WalkIter(val, voxgigstruct.WalkOptions{Order: voxgigstruct.OrderBoth}) {
+		both = append(both, ev.Phase.String())
+	}
+	if both[0] != "enter" || both[len(both)-1] != "exit" {
+		t.Fatalf("expected enter first and exit last under OrderBoth, got %+v", both)
+	}
+}
+
+func TestWalkIterMaxDepth(t *testing.T) {
+	val := map[string]any{"a": map[string]any{"b": map[string]any{"c": float64(1)}}}
+
+	var paths []string
+	for ev := range voxgigstruct.WalkIter(val, voxgigstruct.WalkOptions{MaxDepth: 1}) {
+		if ev.Key != nil {
+			paths = append(paths, *ev.Key)
+		}
+	}
+
+	// At depth 1, "a" itself is cut off from further descent and reported
+	// as a leaf rather than being entered.
+	if len(paths) != 1 || paths[0] != "a" {
+		t.Fatalf("expected only 'a' to be visited under MaxDepth=1, got %+v", paths)
+	}
+}
+
+func TestWalkIterSkipFunc(t *testing.T) {
+	val := map[string]any{"keep": float64(1), "skip": map[string]any{"nested": float64(2)}}
+
+	skip := func(path []string, v any) bool {
+		return len(path) > 0 && path[len(path)-1] == "skip"
+	}
+
+	var keys []string
+	for ev := range voxgigstruct.WalkIter(val, voxgigstruct.WalkOptions{SkipFunc: skip}) {
+		if ev.Key != nil {
+			keys = append(keys, *ev.Key)
+		}
+	}
+
+	for _, k := range keys {
+		if k == "skip" || k == "nested" {
+			t.Fatalf("expected skip subtree to be pruned, got %+v", keys)
+		}
+	}
+}
+
+func TestWalkContextMatchesWalk(t *testing.T) {
+	mk := func() any {
+		return map[string]any{"a": float64(1), "b": map[string]any{"c": float64(2)}}
+	}
+
+	double := func(key *string, val any, parent any, path []string) any {
+		if n, ok := val.(float64); ok {
+			return n * 2
+		}
+		return val
+	}
+
+	wantOut := voxgigstruct.Walk(mk(), double)
+	gotOut := voxgigstruct.WalkContext(context.Background(), mk(), double)
+
+	if fmt.Sprintf("%v", wantOut) != fmt.Sprintf("%v", gotOut) {
+		t.Fatalf("expected WalkContext to match Walk, got %+v vs %+v", gotOut, wantOut)
+	}
+}
+
+func TestWalkContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	val := map[string]any{"a": map[string]any{"b": float64(1)}}
+	identity := func(key *string, val any, parent any, path []string) any { return val }
+
+	// An already-cancelled context aborts before any work is done; the call
+	// must still return cleanly rather than hang or panic.
+	out := voxgigstruct.WalkContext(ctx, val, identity)
+	if out == nil {
+		t.Fatalf("expected WalkContext to return the in-progress value, got nil")
+	}
+}
+
+func deepNest(depth int) map[string]any {
+	top := map[string]any{}
+	cur := top
+	for i := 0; i < depth; i++ {
+		next := map[string]any{}
+		cur["n"] = next
+		cur = next
+	}
+	cur["leaf"] = float64(1)
+	return top
+}
+
+// recursiveWalkDescend is the original call-stack-recursive traversal that
+// WalkDescend used before it was reimplemented on top of the iterative
+// engine in walkiter.go. It's kept here, benchmark-only, as the baseline
+// that demonstrates why the iterative form exists: on deeply nested input
+// it grows the goroutine stack one frame per level, where WalkContext's
+// explicit-stack traversal grows the heap instead.
+func recursiveWalkDescend(val any, apply voxgigstruct.WalkApply, key *string, parent any, path []string) any {
+	if voxgigstruct.IsNode(val) {
+		for _, kv := range voxgigstruct.Items(val) {
+			ckey := kv[0]
+			child := kv[1]
+			ckeyStr := voxgigstruct.StrKey(ckey)
+			newChild := recursiveWalkDescend(child, apply, &ckeyStr, val, append(path, ckeyStr))
+			val = voxgigstruct.SetProp(val, ckey, newChild)
+		}
+
+		if nil != parent && nil != key {
+			voxgigstruct.SetProp(parent, *key, val)
+		}
+	}
+
+	return apply(key, val, parent, path)
+}
+
+func BenchmarkWalkRecursive(b *testing.B) {
+	identity := func(key *string, v any, parent any, path []string) any { return v }
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		recursiveWalkDescend(deepNest(5000), identity, nil, nil, nil)
+	}
+}
+
+func BenchmarkWalkContextIterative(b *testing.B) {
+	identity := func(key *string, v any, parent any, path []string) any { return v }
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		voxgigstruct.WalkContext(ctx, deepNest(5000), identity)
+	}
+}